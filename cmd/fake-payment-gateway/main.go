@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"dating-app-api/deliveries/middlewares"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+)
+
+// fake payment gateway, fires a signed virtual account callback to /user/verify
+// the same way the real gateway does so the premium flow can be tested locally.
+func main() {
+	_ = godotenv.Load()
+
+	host := flag.String("host", fmt.Sprintf("http://localhost:%v/api/%v", os.Getenv("APP_PORT"), os.Getenv("APP_VERSION")), "base url of the api")
+	vaNumber := flag.String("va", "", "virtual account number to pay")
+	amount := flag.Float64("amount", 0, "paid amount")
+	transactionId := flag.String("trx", uuid.NewString(), "gateway transaction id, reuse it to replay a callback")
+	flag.Parse()
+
+	if *vaNumber == "" || *amount <= 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"transaction_id": *transactionId,
+		"va_number":      *vaNumber,
+		"amount":         *amount,
+	})
+	if err != nil {
+		log.Fatalln("error marshal callback body :", err)
+	}
+
+	timeStamp := time.Now().Format("2006-01-02 15:04:05")
+	signature := middlewares.GenerateSignature(os.Getenv("API_KEY"), string(body), timeStamp)
+
+	req, err := http.NewRequest(http.MethodPost, *host+"/user/verify", bytes.NewReader(body))
+	if err != nil {
+		log.Fatalln("error create callback request :", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("timestamp", timeStamp)
+	req.Header.Set("signature", signature)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Fatalln("error send callback :", err)
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		log.Fatalln("error read callback response :", err)
+	}
+
+	fmt.Println(res.Status)
+	fmt.Println(string(resBody))
+}
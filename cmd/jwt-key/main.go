@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// jwt-key tool for ops, writes a new signing key to the jwt key directory.
+// Point JWT_SIGNING_KID at it to rotate, the previous keys stay in the
+// directory until the tokens they signed have expired.
+func main() {
+	dir := flag.String("dir", "keys", "jwt key directory")
+	alg := flag.String("alg", "EdDSA", "key algorithm, RS256 or EdDSA")
+	kid := flag.String("kid", time.Now().UTC().Format("20060102150405"), "key id, the file is named after it")
+	flag.Parse()
+
+	var (
+		private interface{}
+		err     error
+	)
+	switch *alg {
+	case "RS256":
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "EdDSA":
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		flag.Usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Fatalln("error generate key :", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		log.Fatalln("error marshal key :", err)
+	}
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		log.Fatalln("error create key directory :", err)
+	}
+
+	path := filepath.Join(*dir, *kid+".pem")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		log.Fatalln("error create key file :", err)
+	}
+	defer file.Close()
+
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		log.Fatalln("error write key file :", err)
+	}
+
+	fmt.Printf("wrote %v key %v\n", *alg, path)
+}
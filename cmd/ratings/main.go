@@ -0,0 +1,115 @@
+package main
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/repositories"
+	"dating-app-api/utils/rating"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+)
+
+// maxBarWidth is the width of the biggest bucket in the histogram
+const maxBarWidth = 50
+
+// ratings tool for ops, recomputes every desirability rating from the stored
+// swipes and prints the distribution of the ratings as a histogram.
+func main() {
+	_ = godotenv.Load()
+
+	recompute := flag.Bool("recompute", false, "recompute every rating from the stored swipes")
+	histogram := flag.Bool("histogram", false, "print the rating distribution")
+	bucketSize := flag.Float64("bucket", 50, "rating range of one histogram bucket")
+	flag.Parse()
+
+	if (!*recompute && !*histogram) || *bucketSize <= 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	env, errs := configs.InitRatingsEnv()
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Println(err)
+		}
+		log.Fatalln("error init env")
+	}
+
+	db, err := configs.InitDb(env)
+	if err != nil {
+		log.Fatalln("error init db :", err)
+	}
+
+	ratingRepo := repositories.NewRatingRepository(db)
+
+	if *recompute {
+		count, err := recomputeRatings(db, ratingRepo, rating.NewElo(env.RatingK))
+		if err != nil {
+			log.Fatalln("error recompute ratings :", err)
+		}
+		fmt.Printf("recomputed %d ratings\n", count)
+	}
+
+	if *histogram {
+		buckets, err := ratingRepo.GetRatingHistogram(*bucketSize)
+		if err != nil {
+			log.Fatalln("error get rating histogram :", err)
+		}
+		printHistogram(buckets, *bucketSize)
+	}
+}
+
+// recomputeRatings replays every swipe oldest first, swipes applied by the
+// api while it runs are overwritten.
+func recomputeRatings(db *gorm.DB, ratingRepo repositories.RatingRepositoryInterface, elo rating.Elo) (int, error) {
+	table := rating.NewTable(elo)
+
+	err := repositories.NewSwipeRepository(db).EachSwipe(func(swipe *models.SwipeModel) error {
+		table.Apply(rating.Outcome{
+			SwiperId: swipe.SwiperId,
+			TargetId: swipe.TargetId,
+			Right:    swipe.Direction == models.SwipeRight,
+		})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	userRatings := []*models.UserRatingModel{}
+	for _, userId := range table.UserIds() {
+		userRatings = append(userRatings, &models.UserRatingModel{
+			UserId:      userId,
+			Rating:      table.Rating(userId),
+			SwipesCount: table.Swipes(userId),
+		})
+	}
+
+	tx := db.Begin()
+	if err := ratingRepo.ReplaceRatings(userRatings, tx); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	return len(userRatings), tx.Commit().Error
+}
+
+func printHistogram(buckets []*models.RatingBucket, bucketSize float64) {
+	var total, biggest int64
+	for _, bucket := range buckets {
+		total += bucket.Count
+		biggest = max(biggest, bucket.Count)
+	}
+
+	for _, bucket := range buckets {
+		width := int(bucket.Count * maxBarWidth / biggest)
+		fmt.Printf("%6.0f - %-6.0f %8d %s\n", bucket.Floor, bucket.Floor+bucketSize, bucket.Count, strings.Repeat("#", max(width, 1)))
+	}
+	fmt.Printf("%15s %8d\n", "total", total)
+}
@@ -2,9 +2,16 @@ package configs
 
 import (
 	"dating-app-api/utils"
+	"dating-app-api/utils/imageproc"
+	"dating-app-api/utils/jwtkeys"
+	"dating-app-api/utils/moderation"
+	"dating-app-api/utils/ranking"
+	"dating-app-api/utils/rating"
+	"dating-app-api/utils/tokenstore"
 	"errors"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type EnviConfig struct {
@@ -21,8 +28,65 @@ type EnviConfig struct {
 	JwtRKey      string
 	JwtAtExpTime int
 	JwtRtExpTime int
-	Redis        *utils.Redis
-	ApiKey       string
+	// with JwtKeyDir set tokens are signed with the asymmetric key named
+	// JwtSigningKid, otherwise with JwtKey and JwtRKey
+	JwtKeyDir     string
+	JwtSigningKid string
+	JwtKeys       *jwtkeys.KeySet
+	JwtRKeys      *jwtkeys.KeySet
+	// tokens carry JwtIssuer and are only accepted with it, access and
+	// refresh tokens are kept apart by their audience
+	JwtIssuer          string
+	JwtAudience        string
+	JwtRefreshAudience string
+	// a rotated refresh token presented again within JwtRtGraceSeconds is
+	// treated as a concurrent refresh instead of a reuse
+	JwtRtGraceSeconds int
+	Redis             *utils.Redis
+	TokenStore        tokenstore.Store
+	ApiKey            string
+	AdminApiKey       string
+
+	SwipeDedupeDays int
+	SwipeDailyLimit int
+	// a swipe can be rewound within SwipeRewindMinutes, users without the
+	// rewind entitlement get SwipeRewindDailyLimit rewinds a day, 0 makes the
+	// rewind route premium only
+	SwipeRewindMinutes    int
+	SwipeRewindDailyLimit int
+	// super likes a day for users without and with an active subscription
+	SuperLikeDailyLimit        int
+	SuperLikePremiumDailyLimit int
+
+	PaymentVaPrefix      string
+	PaymentExpiryMinutes int
+
+	StorageDriver    string
+	StorageLocalPath string
+	Storage          utils.Storage
+
+	PhotoMinSize   int
+	PhotoWorkers   int
+	PhotoQueueSize int
+	ImageProcessor *imageproc.Processor
+	// the workers of PhotoPool, Ranker and RatingUpdater are only needed by
+	// the api, main sets them instead of InitEnv
+	PhotoPool *imageproc.Pool
+
+	PhotoClassifier moderation.Classifier
+
+	RankWeights  ranking.Weights
+	RankPoolSize int
+	RankSeed     int64
+	RankDebug    bool
+	Ranker       ranking.Ranker
+
+	RatingK         float64
+	RatingQueueSize int
+	RatingUpdater   *rating.Updater
+
+	BoostMinutes    int
+	BoostMultiplier float64
 }
 
 func InitEnv() (EnviConfig, []error) {
@@ -49,30 +113,7 @@ func InitEnv() (EnviConfig, []error) {
 		errs = append(errs, errors.New("app env not found"))
 	}
 
-	env.DbHost = os.Getenv("DB_HOST")
-	if env.DbHost == "" {
-		errs = append(errs, errors.New("db host env not found"))
-	}
-
-	env.DbPort = os.Getenv("DB_PORT")
-	if env.DbPort == "" {
-		errs = append(errs, errors.New("db port env not found"))
-	}
-
-	env.DbUsername = os.Getenv("DB_USERNAME")
-	if env.DbUsername == "" {
-		errs = append(errs, errors.New("db username env not found"))
-	}
-
-	env.DbPassword = os.Getenv("DB_PASSWORD")
-	if env.DbPassword == "" {
-		errs = append(errs, errors.New("db password env not found"))
-	}
-
-	env.DbName = os.Getenv("DB_NAME")
-	if env.DbName == "" {
-		errs = append(errs, errors.New("db name env not found"))
-	}
+	errs = append(errs, initDbEnv(&env)...)
 
 	redisPort, err := strconv.Atoi(os.Getenv("REDIS_PORT"))
 	if err != nil {
@@ -101,13 +142,52 @@ func InitEnv() (EnviConfig, []error) {
 	}
 
 	env.JwtKey = os.Getenv("JWT_KEY")
-	if env.JwtKey == "" {
-		errs = append(errs, errors.New("jwt key env not found"))
+	env.JwtRKey = os.Getenv("REFRESH_KEY")
+	env.JwtKeyDir = os.Getenv("JWT_KEY_DIR")
+	env.JwtSigningKid = os.Getenv("JWT_SIGNING_KID")
+	if env.JwtKeyDir == "" {
+		if env.JwtKey == "" {
+			errs = append(errs, errors.New("jwt key env not found"))
+		}
+
+		if env.JwtRKey == "" {
+			errs = append(errs, errors.New("jwt refresh key env not found"))
+		}
+
+		env.JwtKeys = jwtkeys.NewHMACKeySet(env.JwtKey)
+		env.JwtRKeys = jwtkeys.NewHMACKeySet(env.JwtRKey)
+	} else {
+		keys, err := jwtkeys.LoadKeySet(env.JwtKeyDir, env.JwtSigningKid)
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			// tokens signed with the shared secrets before the switch stay
+			// valid until they expire
+			env.JwtKeys, env.JwtRKeys = keys, keys
+			if env.JwtKey != "" && env.JwtRKey != "" {
+				env.JwtKeys = keys.WithLegacy(jwtkeys.NewHMACKey(env.JwtKey))
+				env.JwtRKeys = keys.WithLegacy(jwtkeys.NewHMACKey(env.JwtRKey))
+			}
+		}
 	}
 
-	env.JwtRKey = os.Getenv("REFRESH_KEY")
-	if env.JwtKey == "" {
-		errs = append(errs, errors.New("jwt refresh key env not found"))
+	env.JwtIssuer = os.Getenv("JWT_ISSUER")
+	if env.JwtIssuer == "" {
+		env.JwtIssuer = "dating-app-api/" + env.AppEnv
+	}
+
+	env.JwtAudience = os.Getenv("JWT_AUDIENCE")
+	if env.JwtAudience == "" {
+		env.JwtAudience = "dating-app-api"
+	}
+
+	env.JwtRefreshAudience = os.Getenv("JWT_REFRESH_AUDIENCE")
+	if env.JwtRefreshAudience == "" {
+		env.JwtRefreshAudience = env.JwtAudience + "/refresh"
+	}
+
+	if env.JwtAudience == env.JwtRefreshAudience {
+		errs = append(errs, errors.New("jwt audience and refresh audience env must differ"))
 	}
 
 	env.JwtAtExpTime, err = strconv.Atoi(os.Getenv("JWT_AT_EXP"))
@@ -120,11 +200,216 @@ func InitEnv() (EnviConfig, []error) {
 		errs = append(errs, errors.New("jwt refresh expired env not found or invalid"))
 	}
 
+	env.JwtRtGraceSeconds = 10
+	if grace := os.Getenv("JWT_RT_GRACE"); grace != "" {
+		env.JwtRtGraceSeconds, err = strconv.Atoi(grace)
+		if err != nil || env.JwtRtGraceSeconds < 0 {
+			errs = append(errs, errors.New("jwt refresh grace env invalid"))
+		}
+	}
+
 	env.ApiKey = os.Getenv("API_KEY")
 	if env.ApiKey == "" {
 		errs = append(errs, errors.New("api key env not found"))
 	}
 
+	// admin endpoints are disabled when no admin api key is set
+	env.AdminApiKey = os.Getenv("ADMIN_API_KEY")
+
+	env.SwipeDedupeDays = 1
+	if dedupeDays := os.Getenv("SWIPE_DEDUPE_DAYS"); dedupeDays != "" {
+		env.SwipeDedupeDays, err = strconv.Atoi(dedupeDays)
+		if err != nil || env.SwipeDedupeDays < 1 {
+			errs = append(errs, errors.New("swipe dedupe days env invalid"))
+		}
+	}
+
+	env.SwipeDailyLimit = 10
+	if dailyLimit := os.Getenv("SWIPE_DAILY_LIMIT"); dailyLimit != "" {
+		env.SwipeDailyLimit, err = strconv.Atoi(dailyLimit)
+		if err != nil || env.SwipeDailyLimit < 0 {
+			errs = append(errs, errors.New("swipe daily limit env invalid"))
+		}
+	}
+
+	env.SwipeRewindMinutes = 5
+	if rewindMinutes := os.Getenv("SWIPE_REWIND_MINUTES"); rewindMinutes != "" {
+		env.SwipeRewindMinutes, err = strconv.Atoi(rewindMinutes)
+		if err != nil || env.SwipeRewindMinutes < 1 {
+			errs = append(errs, errors.New("swipe rewind minutes env invalid"))
+		}
+	}
+
+	env.SwipeRewindDailyLimit = 1
+	if rewindLimit := os.Getenv("SWIPE_REWIND_DAILY_LIMIT"); rewindLimit != "" {
+		env.SwipeRewindDailyLimit, err = strconv.Atoi(rewindLimit)
+		if err != nil || env.SwipeRewindDailyLimit < 0 {
+			errs = append(errs, errors.New("swipe rewind daily limit env invalid"))
+		}
+	}
+
+	env.SuperLikeDailyLimit = 1
+	if superLimit := os.Getenv("SUPER_LIKE_DAILY_LIMIT"); superLimit != "" {
+		env.SuperLikeDailyLimit, err = strconv.Atoi(superLimit)
+		if err != nil || env.SuperLikeDailyLimit < 0 {
+			errs = append(errs, errors.New("super like daily limit env invalid"))
+		}
+	}
+
+	env.SuperLikePremiumDailyLimit = 5
+	if superLimit := os.Getenv("SUPER_LIKE_PREMIUM_DAILY_LIMIT"); superLimit != "" {
+		env.SuperLikePremiumDailyLimit, err = strconv.Atoi(superLimit)
+		if err != nil || env.SuperLikePremiumDailyLimit < 0 {
+			errs = append(errs, errors.New("super like premium daily limit env invalid"))
+		}
+	}
+
+	env.PaymentVaPrefix = os.Getenv("PAYMENT_VA_PREFIX")
+	if len(env.PaymentVaPrefix) != 4 {
+		errs = append(errs, errors.New("payment va prefix env not found or invalid"))
+	}
+
+	env.PaymentExpiryMinutes = 24 * 60
+	if expiry := os.Getenv("PAYMENT_EXPIRY_MINUTES"); expiry != "" {
+		env.PaymentExpiryMinutes, err = strconv.Atoi(expiry)
+		if err != nil || env.PaymentExpiryMinutes < 1 {
+			errs = append(errs, errors.New("payment expiry minutes env invalid"))
+		}
+	}
+
+	confStorage := utils.ConfStorage{
+		Driver:      os.Getenv("STORAGE_DRIVER"),
+		LocalPath:   os.Getenv("STORAGE_LOCAL_PATH"),
+		BaseUrl:     os.Getenv("STORAGE_BASE_URL"),
+		S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+		S3Region:    os.Getenv("S3_REGION"),
+		S3Bucket:    os.Getenv("S3_BUCKET"),
+		S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+		S3UseSSL:    os.Getenv("S3_USE_SSL") == "true",
+	}
+
+	if confStorage.Driver == "" {
+		confStorage.Driver = utils.StorageLocal
+	}
+
+	switch confStorage.Driver {
+	case utils.StorageLocal:
+		if confStorage.LocalPath == "" {
+			confStorage.LocalPath = "uploads"
+		}
+		if confStorage.BaseUrl == "" {
+			errs = append(errs, errors.New("storage base url env not found"))
+		}
+	case utils.StorageS3:
+		if confStorage.S3Endpoint == "" || confStorage.S3Bucket == "" {
+			errs = append(errs, errors.New("s3 endpoint or bucket env not found"))
+		}
+		if confStorage.S3AccessKey == "" || confStorage.S3SecretKey == "" {
+			errs = append(errs, errors.New("s3 access key or secret key env not found"))
+		}
+	default:
+		errs = append(errs, errors.New("storage driver env invalid"))
+	}
+	env.StorageDriver = confStorage.Driver
+	env.StorageLocalPath = confStorage.LocalPath
+
+	env.PhotoMinSize = 320
+	if minSize := os.Getenv("PHOTO_MIN_SIZE"); minSize != "" {
+		env.PhotoMinSize, err = strconv.Atoi(minSize)
+		if err != nil || env.PhotoMinSize < 1 {
+			errs = append(errs, errors.New("photo min size env invalid"))
+		}
+	}
+
+	env.PhotoWorkers = 2
+	if workers := os.Getenv("PHOTO_WORKERS"); workers != "" {
+		env.PhotoWorkers, err = strconv.Atoi(workers)
+		if err != nil || env.PhotoWorkers < 1 {
+			errs = append(errs, errors.New("photo workers env invalid"))
+		}
+	}
+
+	env.PhotoQueueSize = 32
+	if queueSize := os.Getenv("PHOTO_QUEUE_SIZE"); queueSize != "" {
+		env.PhotoQueueSize, err = strconv.Atoi(queueSize)
+		if err != nil || env.PhotoQueueSize < 1 {
+			errs = append(errs, errors.New("photo queue size env invalid"))
+		}
+	}
+
+	classifier := os.Getenv("PHOTO_CLASSIFIER")
+	if classifier == "" {
+		classifier = moderation.ClassifierNoop
+	}
+	env.PhotoClassifier, err = moderation.NewClassifier(classifier)
+	if err != nil {
+		errs = append(errs, errors.New("photo classifier env invalid"))
+	}
+
+	env.RankWeights = ranking.Weights{
+		Recency:         0.25,
+		Distance:        0.2,
+		Completeness:    0.15,
+		SharedInterests: 0.15,
+		Desirability:    0.2,
+		Jitter:          0.05,
+	}
+	rankWeights := []struct {
+		env    string
+		weight *float64
+	}{
+		{"RANK_WEIGHT_RECENCY", &env.RankWeights.Recency},
+		{"RANK_WEIGHT_DISTANCE", &env.RankWeights.Distance},
+		{"RANK_WEIGHT_COMPLETENESS", &env.RankWeights.Completeness},
+		{"RANK_WEIGHT_SHARED_INTERESTS", &env.RankWeights.SharedInterests},
+		{"RANK_WEIGHT_DESIRABILITY", &env.RankWeights.Desirability},
+		{"RANK_WEIGHT_JITTER", &env.RankWeights.Jitter},
+	}
+	for _, rankWeight := range rankWeights {
+		if weight := os.Getenv(rankWeight.env); weight != "" {
+			*rankWeight.weight, err = strconv.ParseFloat(weight, 64)
+			if err != nil || *rankWeight.weight < 0 {
+				errs = append(errs, errors.New(strings.ToLower(strings.ReplaceAll(rankWeight.env, "_", " "))+" env invalid"))
+			}
+		}
+	}
+
+	env.RankPoolSize = 200
+	if poolSize := os.Getenv("RANK_POOL_SIZE"); poolSize != "" {
+		env.RankPoolSize, err = strconv.Atoi(poolSize)
+		if err != nil || env.RankPoolSize < 1 {
+			errs = append(errs, errors.New("rank pool size env invalid"))
+		}
+	}
+
+	if seed := os.Getenv("RANK_SEED"); seed != "" {
+		env.RankSeed, err = strconv.ParseInt(seed, 10, 64)
+		if err != nil {
+			errs = append(errs, errors.New("rank seed env invalid"))
+		}
+	}
+
+	env.RankDebug = os.Getenv("RANK_DEBUG") == "true"
+
+	errs = append(errs, initRatingEnv(&env)...)
+
+	env.BoostMinutes = 30
+	if boostMinutes := os.Getenv("BOOST_MINUTES"); boostMinutes != "" {
+		env.BoostMinutes, err = strconv.Atoi(boostMinutes)
+		if err != nil || env.BoostMinutes < 1 {
+			errs = append(errs, errors.New("boost minutes env invalid"))
+		}
+	}
+
+	env.BoostMultiplier = 2
+	if multiplier := os.Getenv("BOOST_MULTIPLIER"); multiplier != "" {
+		env.BoostMultiplier, err = strconv.ParseFloat(multiplier, 64)
+		if err != nil || env.BoostMultiplier < 1 {
+			errs = append(errs, errors.New("boost multiplier env invalid"))
+		}
+	}
+
 	if len(errs) > 0 {
 		return env, errs
 	} else {
@@ -134,7 +419,85 @@ func InitEnv() (EnviConfig, []error) {
 			return env, errs
 		}
 		env.Redis = redisClient
+		env.TokenStore = tokenstore.NewRedisStore(redisClient)
+
+		storage, err := utils.NewStorage(confStorage)
+		if err != nil {
+			errs = append(errs, err)
+			return env, errs
+		}
+		env.Storage = storage
+
+		env.ImageProcessor = imageproc.NewProcessor(imageproc.ConfProcessor{
+			MinSize: env.PhotoMinSize,
+		})
 	}
 
 	return env, nil
 }
+
+// InitRatingsEnv only reads what the ratings tool needs, the database and the
+// rating settings.
+func InitRatingsEnv() (EnviConfig, []error) {
+	var env EnviConfig
+	var errs []error
+
+	errs = append(errs, initDbEnv(&env)...)
+	errs = append(errs, initRatingEnv(&env)...)
+
+	return env, errs
+}
+
+func initDbEnv(env *EnviConfig) []error {
+	var errs []error
+
+	env.DbHost = os.Getenv("DB_HOST")
+	if env.DbHost == "" {
+		errs = append(errs, errors.New("db host env not found"))
+	}
+
+	env.DbPort = os.Getenv("DB_PORT")
+	if env.DbPort == "" {
+		errs = append(errs, errors.New("db port env not found"))
+	}
+
+	env.DbUsername = os.Getenv("DB_USERNAME")
+	if env.DbUsername == "" {
+		errs = append(errs, errors.New("db username env not found"))
+	}
+
+	env.DbPassword = os.Getenv("DB_PASSWORD")
+	if env.DbPassword == "" {
+		errs = append(errs, errors.New("db password env not found"))
+	}
+
+	env.DbName = os.Getenv("DB_NAME")
+	if env.DbName == "" {
+		errs = append(errs, errors.New("db name env not found"))
+	}
+
+	return errs
+}
+
+func initRatingEnv(env *EnviConfig) []error {
+	var errs []error
+	var err error
+
+	env.RatingK = rating.DefaultK
+	if k := os.Getenv("RATING_K"); k != "" {
+		env.RatingK, err = strconv.ParseFloat(k, 64)
+		if err != nil || env.RatingK <= 0 {
+			errs = append(errs, errors.New("rating k env invalid"))
+		}
+	}
+
+	env.RatingQueueSize = 256
+	if queueSize := os.Getenv("RATING_QUEUE_SIZE"); queueSize != "" {
+		env.RatingQueueSize, err = strconv.Atoi(queueSize)
+		if err != nil || env.RatingQueueSize < 1 {
+			errs = append(errs, errors.New("rating queue size env invalid"))
+		}
+	}
+
+	return errs
+}
@@ -15,6 +15,9 @@ type AuthHandlerInterface interface {
 	Login(c *fiber.Ctx) error
 	RefreshToken(c *fiber.Ctx) error
 	LogOut(c *fiber.Ctx) error
+	GetListSession(c *fiber.Ctx) error
+	RevokeSession(c *fiber.Ctx) error
+	RevokeAllSessions(c *fiber.Ctx) error
 }
 
 type authHandler struct {
@@ -45,6 +48,8 @@ func (h *authHandler) Login(c *fiber.Ctx) error {
 		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
 	}
 
+	request.Ip = c.IP()
+	request.UserAgent = string(c.Request().Header.UserAgent())
 	res := h.service.Login(request)
 	return c.Status(res.StatusCode).JSON(res)
 }
@@ -58,3 +63,18 @@ func (h *authHandler) RefreshToken(c *fiber.Ctx) error {
 	res := h.service.RefreshToken(c.Context())
 	return c.Status(res.StatusCode).JSON(res)
 }
+
+func (h *authHandler) GetListSession(c *fiber.Ctx) error {
+	res := h.service.GetListSession(c.Context())
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *authHandler) RevokeSession(c *fiber.Ctx) error {
+	res := h.service.RevokeSession(c.Context(), c.Params("id"))
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *authHandler) RevokeAllSessions(c *fiber.Ctx) error {
+	res := h.service.RevokeAllSessions(c.Context())
+	return c.Status(res.StatusCode).JSON(res)
+}
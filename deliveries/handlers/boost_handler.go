@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/services"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BoostHandlerInterface interface {
+	Boost(c *fiber.Ctx) error
+	GetDetail(c *fiber.Ctx) error
+	GetCredit(c *fiber.Ctx) error
+	GrantCredit(c *fiber.Ctx) error
+}
+
+type boostHandler struct {
+	service services.BoostServiceInterface
+	resp    responses.CommondResponse
+	db      *gorm.DB
+}
+
+func NewBoostHandler(service services.BoostServiceInterface, resp responses.CommondResponse, db *gorm.DB) BoostHandlerInterface {
+	return &boostHandler{
+		service: service,
+		resp:    resp,
+		db:      db,
+	}
+}
+
+func (h *boostHandler) Boost(c *fiber.Ctx) error {
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[boostHandler][Boost] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.Boost(c.Context(), dbTx)
+	if res.StatusCode != http.StatusCreated {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[boostHandler][Boost] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[boostHandler][Boost] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *boostHandler) GetDetail(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, "invalid id"))
+	}
+
+	res := h.service.GetDetail(c.Context(), id)
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *boostHandler) GetCredit(c *fiber.Ctx) error {
+	res := h.service.GetCredit(c.Context())
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *boostHandler) GrantCredit(c *fiber.Ctx) error {
+	request := new(requests.GrantBoostCreditRequest)
+	err := c.BodyParser(request)
+	if err != nil {
+		log.Println("[boostHandler][GrantCredit] parse request body error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadateGrantBoostCredit()
+	if validate != nil {
+		log.Println("[boostHandler][GrantCredit] validate request body :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[boostHandler][GrantCredit] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.GrantCredit(request, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[boostHandler][GrantCredit] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[boostHandler][GrantCredit] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/services"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MatchHandlerInterface interface {
+	GetListMatch(c *fiber.Ctx) error
+	Unmatch(c *fiber.Ctx) error
+}
+
+type matchHandler struct {
+	service services.MatchServiceInterface
+	resp    responses.CommondResponse
+	db      *gorm.DB
+}
+
+func NewMatchHandler(service services.MatchServiceInterface, resp responses.CommondResponse, db *gorm.DB) MatchHandlerInterface {
+	return &matchHandler{
+		service: service,
+		resp:    resp,
+		db:      db,
+	}
+}
+
+func (h *matchHandler) GetListMatch(c *fiber.Ctx) error {
+	meta := new(requests.MetaPaginationRequest)
+	err := c.QueryParser(meta)
+	if err != nil {
+		log.Println("[matchHandler][GetListMatch] parse query params error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+	meta.ParsePagination()
+
+	res := h.service.GetList(c.Context(), meta)
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *matchHandler) Unmatch(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, "invalid id"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[matchHandler][Unmatch] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.Unmatch(c.Context(), id, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[matchHandler][Unmatch] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[matchHandler][Unmatch] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/services"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ModerationHandlerInterface interface {
+	GetListQueue(c *fiber.Ctx) error
+	ApprovePhoto(c *fiber.Ctx) error
+	RejectPhoto(c *fiber.Ctx) error
+}
+
+type moderationHandler struct {
+	service services.ModerationServiceInterface
+	resp    responses.CommondResponse
+	db      *gorm.DB
+}
+
+func NewModerationHandler(service services.ModerationServiceInterface, resp responses.CommondResponse, db *gorm.DB) ModerationHandlerInterface {
+	return &moderationHandler{
+		service: service,
+		resp:    resp,
+		db:      db,
+	}
+}
+
+func (h *moderationHandler) GetListQueue(c *fiber.Ctx) error {
+	meta := new(requests.MetaPaginationRequest)
+	err := c.QueryParser(meta)
+	if err != nil {
+		log.Println("[moderationHandler][GetListQueue] parse query params error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	// the queue is worked oldest first
+	if meta.Order == "" {
+		meta.Order = "ASC"
+	}
+	meta.ParsePagination()
+
+	res := h.service.GetListQueue(meta)
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *moderationHandler) ApprovePhoto(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, "invalid id"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[moderationHandler][ApprovePhoto] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.ApprovePhoto(id, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[moderationHandler][ApprovePhoto] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[moderationHandler][ApprovePhoto] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *moderationHandler) RejectPhoto(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, "invalid id"))
+	}
+
+	request := new(requests.RejectPhotoRequest)
+	err := c.BodyParser(request)
+	if err != nil {
+		log.Println("[moderationHandler][RejectPhoto] parse request body error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadateRejectPhoto()
+	if validate != nil {
+		log.Println("[moderationHandler][RejectPhoto] validate request body :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[moderationHandler][RejectPhoto] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.RejectPhoto(id, request, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[moderationHandler][RejectPhoto] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[moderationHandler][RejectPhoto] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
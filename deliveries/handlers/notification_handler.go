@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/services"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type NotificationHandlerInterface interface {
+	GetListNotification(c *fiber.Ctx) error
+	ReadNotification(c *fiber.Ctx) error
+}
+
+type notificationHandler struct {
+	service services.NotificationServiceInterface
+	resp    responses.CommondResponse
+	db      *gorm.DB
+}
+
+func NewNotificationHandler(service services.NotificationServiceInterface, resp responses.CommondResponse, db *gorm.DB) NotificationHandlerInterface {
+	return &notificationHandler{
+		service: service,
+		resp:    resp,
+		db:      db,
+	}
+}
+
+func (h *notificationHandler) GetListNotification(c *fiber.Ctx) error {
+	meta := new(requests.MetaPaginationRequest)
+	err := c.QueryParser(meta)
+	if err != nil {
+		log.Println("[notificationHandler][GetListNotification] parse query params error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+	meta.ParsePagination()
+
+	res := h.service.GetList(c.Context(), meta)
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *notificationHandler) ReadNotification(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, "invalid id"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[notificationHandler][ReadNotification] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.Read(c.Context(), id, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[notificationHandler][ReadNotification] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[notificationHandler][ReadNotification] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
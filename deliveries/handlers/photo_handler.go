@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/services"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PhotoHandlerInterface interface {
+	UploadPhoto(c *fiber.Ctx) error
+	GetListPhoto(c *fiber.Ctx) error
+	ReorderPhoto(c *fiber.Ctx) error
+	SetPrimaryPhoto(c *fiber.Ctx) error
+	DeletePhoto(c *fiber.Ctx) error
+}
+
+type photoHandler struct {
+	service services.PhotoServiceInterface
+	resp    responses.CommondResponse
+	db      *gorm.DB
+}
+
+func NewPhotoHandler(service services.PhotoServiceInterface, resp responses.CommondResponse, db *gorm.DB) PhotoHandlerInterface {
+	return &photoHandler{
+		service: service,
+		resp:    resp,
+		db:      db,
+	}
+}
+
+func (h *photoHandler) UploadPhoto(c *fiber.Ctx) error {
+	file, err := c.FormFile("photo")
+	if err != nil {
+		log.Println("[photoHandler][UploadPhoto] parse form file error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, "photo is required"))
+	}
+
+	res := h.service.UploadPhoto(c.Context(), file)
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *photoHandler) GetListPhoto(c *fiber.Ctx) error {
+	res := h.service.GetListPhoto(c.Context())
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *photoHandler) ReorderPhoto(c *fiber.Ctx) error {
+	request := new(requests.ReorderPhotoRequest)
+	err := c.BodyParser(request)
+	if err != nil {
+		log.Println("[photoHandler][ReorderPhoto] parse request body error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadateReorderPhoto()
+	if validate != nil {
+		log.Println("[photoHandler][ReorderPhoto] validate request body :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[photoHandler][ReorderPhoto] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.ReorderPhoto(c.Context(), request, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[photoHandler][ReorderPhoto] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[photoHandler][ReorderPhoto] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *photoHandler) SetPrimaryPhoto(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, "invalid id"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[photoHandler][SetPrimaryPhoto] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.SetPrimaryPhoto(c.Context(), id, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[photoHandler][SetPrimaryPhoto] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[photoHandler][SetPrimaryPhoto] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *photoHandler) DeletePhoto(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, "invalid id"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[photoHandler][DeletePhoto] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.DeletePhoto(c.Context(), id, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[photoHandler][DeletePhoto] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[photoHandler][DeletePhoto] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/services"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type PreferenceHandlerInterface interface {
+	GetPreference(c *fiber.Ctx) error
+	UpdatePreference(c *fiber.Ctx) error
+}
+
+type preferenceHandler struct {
+	service services.PreferenceServiceInterface
+	resp    responses.CommondResponse
+	db      *gorm.DB
+}
+
+func NewPreferenceHandler(service services.PreferenceServiceInterface, resp responses.CommondResponse, db *gorm.DB) PreferenceHandlerInterface {
+	return &preferenceHandler{
+		service: service,
+		resp:    resp,
+		db:      db,
+	}
+}
+
+func (h *preferenceHandler) GetPreference(c *fiber.Ctx) error {
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[preferenceHandler][GetPreference] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.GetPreference(c.Context(), dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[preferenceHandler][GetPreference] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[preferenceHandler][GetPreference] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *preferenceHandler) UpdatePreference(c *fiber.Ctx) error {
+	request := new(requests.PreferenceRequest)
+	err := c.BodyParser(request)
+	if err != nil {
+		log.Println("[preferenceHandler][UpdatePreference] parse request body error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadatePreference()
+	if validate != nil {
+		log.Println("[preferenceHandler][UpdatePreference] validate request body :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[preferenceHandler][UpdatePreference] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.UpdatePreference(c.Context(), request, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[preferenceHandler][UpdatePreference] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[preferenceHandler][UpdatePreference] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
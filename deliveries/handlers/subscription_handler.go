@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"dating-app-api/entities/responses"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SubscriptionHandlerInterface interface {
+	GetSubscription(c *fiber.Ctx) error
+	GetListPlan(c *fiber.Ctx) error
+}
+
+type subscriptionHandler struct {
+	service services.SubscriptionServiceInterface
+	resp    responses.CommondResponse
+}
+
+func NewSubscriptionHandler(service services.SubscriptionServiceInterface, resp responses.CommondResponse) SubscriptionHandlerInterface {
+	return &subscriptionHandler{
+		service: service,
+		resp:    resp,
+	}
+}
+
+func (h *subscriptionHandler) GetSubscription(c *fiber.Ctx) error {
+	res := h.service.GetSubscription(c.Context())
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *subscriptionHandler) GetListPlan(c *fiber.Ctx) error {
+	res := h.service.GetListPlan()
+	return c.Status(res.StatusCode).JSON(res)
+}
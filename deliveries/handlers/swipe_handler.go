@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/services"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type SwipeHandlerInterface interface {
+	Swipe(c *fiber.Ctx) error
+	GetQuota(c *fiber.Ctx) error
+	GetListReceivedLikes(c *fiber.Ctx) error
+	Rewind(c *fiber.Ctx) error
+}
+
+type swipeHandler struct {
+	service services.SwipeServiceInterface
+	resp    responses.CommondResponse
+	db      *gorm.DB
+}
+
+func NewSwipeHandler(service services.SwipeServiceInterface, resp responses.CommondResponse, db *gorm.DB) SwipeHandlerInterface {
+	return &swipeHandler{
+		service: service,
+		resp:    resp,
+		db:      db,
+	}
+}
+
+func (h *swipeHandler) Swipe(c *fiber.Ctx) error {
+	request := new(requests.SwipeRequest)
+	err := c.BodyParser(request)
+	if err != nil {
+		log.Println("[swipeHandler][Swipe] parse request body error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadateSwipe()
+	if validate != nil {
+		log.Println("[swipeHandler][Swipe] validate request body :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[swipeHandler][Swipe] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.SwipService(c.Context(), request, dbTx)
+	if res.StatusCode != http.StatusCreated {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[swipeHandler][Swipe] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[swipeHandler][Swipe] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *swipeHandler) Rewind(c *fiber.Ctx) error {
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[swipeHandler][Rewind] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.Rewind(c.Context(), dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[swipeHandler][Rewind] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[swipeHandler][Rewind] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *swipeHandler) GetQuota(c *fiber.Ctx) error {
+	res := h.service.GetQuota(c.Context())
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *swipeHandler) GetListReceivedLikes(c *fiber.Ctx) error {
+	meta := new(requests.MetaPaginationRequest)
+	err := c.QueryParser(meta)
+	if err != nil {
+		log.Println("[swipeHandler][GetListReceivedLikes] parse query params error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+	meta.ParsePagination()
+
+	res := h.service.GetListReceivedLikes(c.Context(), meta)
+	return c.Status(res.StatusCode).JSON(res)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"dating-app-api/entities/responses"
+	"dating-app-api/services"
+	"dating-app-api/utils/imageproc"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type UploadHandlerInterface interface {
+	GetPhotoFile(c *fiber.Ctx) error
+	GetModerationPhotoFile(c *fiber.Ctx) error
+}
+
+type uploadHandler struct {
+	service services.PhotoServiceInterface
+	resp    responses.CommondResponse
+	path    string
+}
+
+func NewUploadHandler(service services.PhotoServiceInterface, resp responses.CommondResponse, path string) UploadHandlerInterface {
+	return &uploadHandler{
+		service: service,
+		resp:    resp,
+		path:    path,
+	}
+}
+
+// GetPhotoFile serves a photo variant of the local storage, only photos other
+// users may see are served.
+func (h *uploadHandler) GetPhotoFile(c *fiber.Ctx) error {
+	filePath, ok := h.photoFilePath(c)
+	if !ok {
+		return c.Status(404).JSON(h.resp.StatusNotFound("photo not found"))
+	}
+
+	res := h.service.GetVisiblePhoto(fmt.Sprintf("photos/%s/%s", c.Params("userId"), c.Params("photoId")))
+	if res.StatusCode != http.StatusOK {
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	return c.SendFile(filePath)
+}
+
+// GetModerationPhotoFile serves a photo variant whatever its state, it is
+// mounted behind the admin key for the moderation queue.
+func (h *uploadHandler) GetModerationPhotoFile(c *fiber.Ctx) error {
+	filePath, ok := h.photoFilePath(c)
+	if !ok {
+		return c.Status(404).JSON(h.resp.StatusNotFound("photo not found"))
+	}
+
+	return c.SendFile(filePath)
+}
+
+// photoFilePath resolves the file of the requested variant, the params are
+// checked so the path can not leave the photo folder.
+func (h *uploadHandler) photoFilePath(c *fiber.Ctx) (string, bool) {
+	userId := c.Params("userId")
+	photoId := c.Params("photoId")
+	if _, err := uuid.Parse(userId); err != nil {
+		return "", false
+	}
+	if _, err := uuid.Parse(photoId); err != nil {
+		return "", false
+	}
+
+	for _, variant := range imageproc.Variants {
+		if c.Params("file") == variant.Name+".jpg" {
+			return filepath.Join(h.path, "photos", userId, photoId, c.Params("file")), true
+		}
+	}
+
+	return "", false
+}
@@ -19,9 +19,15 @@ type UserHandlerInterface interface {
 	RegisterUser(c *fiber.Ctx) error
 	GetDetailUser(c *fiber.Ctx) error
 	GetMe(c *fiber.Ctx) error
+	Discover(c *fiber.Ctx) error
 	CheckUsername(c *fiber.Ctx) error
 	VerifyUser(c *fiber.Ctx) error
+	CreatePayment(c *fiber.Ctx) error
 	ChangePassword(c *fiber.Ctx) error
+	UpdateUser(c *fiber.Ctx) error
+	GetProfile(c *fiber.Ctx) error
+	UpdateProfile(c *fiber.Ctx) error
+	UpdateLocation(c *fiber.Ctx) error
 }
 
 type userHandler struct {
@@ -64,6 +70,8 @@ func (h *userHandler) RegisterUser(c *fiber.Ctx) error {
 		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
 	}
 
+	request.Ip = c.IP()
+	request.UserAgent = string(c.Request().Header.UserAgent())
 	res := h.service.RegisterUser(request, dbTx)
 	if res.StatusCode != http.StatusCreated {
 		roll := dbTx.Rollback()
@@ -74,7 +82,7 @@ func (h *userHandler) RegisterUser(c *fiber.Ctx) error {
 		return c.Status(res.StatusCode).JSON(res)
 	}
 
-	comm := dbTx.Commit()
+	comm := helpers.CommitTx(dbTx)
 	if comm.Error != nil {
 		log.Println("[userHandler][RegisterUser] error commit db transaction :", comm.Error)
 		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
@@ -103,6 +111,32 @@ func (h *userHandler) GetMe(c *fiber.Ctx) error {
 	return c.Status(res.StatusCode).JSON(res)
 }
 
+func (h *userHandler) Discover(c *fiber.Ctx) error {
+	meta := new(requests.MetaPaginationRequest)
+	err := c.QueryParser(meta)
+	if err != nil {
+		log.Println("[userHandler][Discover] parse query params error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+	meta.ParsePagination()
+
+	request := new(requests.DiscoverRequest)
+	err = c.QueryParser(request)
+	if err != nil {
+		log.Println("[userHandler][Discover] parse query params error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadateDiscover()
+	if validate != nil {
+		log.Println("[userHandler][Discover] validate query params :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	res := h.service.GetList(c.Context(), meta, request)
+	return c.Status(res.StatusCode).JSON(res)
+}
+
 func (h *userHandler) CheckUsername(c *fiber.Ctx) error {
 	request := new(requests.CreateUserRequest)
 	err := c.BodyParser(request)
@@ -141,8 +175,11 @@ func (h *userHandler) VerifyUser(c *fiber.Ctx) error {
 		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
 	}
 
+	// every handled callback is recorded, only server errors are rolled back
+	request.Ip = c.IP()
+	request.UserAgent = string(c.Request().Header.UserAgent())
 	res := h.service.VerifyUser(request, dbTx)
-	if res.StatusCode != http.StatusCreated {
+	if res.StatusCode >= http.StatusInternalServerError {
 		roll := dbTx.Rollback()
 		if roll.Error != nil {
 			log.Println("[userHandler][VerifyUser] error rollback db transaction :", roll.Error)
@@ -151,7 +188,7 @@ func (h *userHandler) VerifyUser(c *fiber.Ctx) error {
 		return c.Status(res.StatusCode).JSON(res)
 	}
 
-	comm := dbTx.Commit()
+	comm := helpers.CommitTx(dbTx)
 	if comm.Error != nil {
 		log.Println("[userHandler][VerifyUser] error commit db transaction :", comm.Error)
 		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
@@ -160,6 +197,44 @@ func (h *userHandler) VerifyUser(c *fiber.Ctx) error {
 
 }
 
+func (h *userHandler) CreatePayment(c *fiber.Ctx) error {
+	request := new(requests.CreatePaymentRequest)
+	err := c.BodyParser(request)
+	if err != nil {
+		log.Println("[userHandler][CreatePayment] parse request body error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadateCreatePayment()
+	if validate != nil {
+		log.Println("[userHandler][CreatePayment] validate request body :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[userHandler][CreatePayment] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.CreatePayment(c.Context(), request, dbTx)
+	if res.StatusCode != http.StatusCreated {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[userHandler][CreatePayment] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[userHandler][CreatePayment] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+	return c.Status(res.StatusCode).JSON(res)
+}
+
 func (h *userHandler) ChangePassword(c *fiber.Ctx) error {
 	request := new(requests.AuthRequest)
 	err := c.BodyParser(request)
@@ -190,7 +265,7 @@ func (h *userHandler) ChangePassword(c *fiber.Ctx) error {
 		return c.Status(res.StatusCode).JSON(res)
 	}
 
-	comm := dbTx.Commit()
+	comm := helpers.CommitTx(dbTx)
 	if comm.Error != nil {
 		log.Println("[userHandler][ChangePassword] error commit db transaction :", comm.Error)
 		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
@@ -198,3 +273,123 @@ func (h *userHandler) ChangePassword(c *fiber.Ctx) error {
 	return c.Status(res.StatusCode).JSON(res)
 
 }
+
+func (h *userHandler) UpdateUser(c *fiber.Ctx) error {
+	request := new(requests.UpdateUserRequest)
+	err := c.BodyParser(request)
+	if err != nil {
+		log.Println("[userHandler][UpdateUser] parse request body error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadateUpdateUser()
+	if validate != nil {
+		log.Println("[userHandler][UpdateUser] validate request body :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[userHandler][UpdateUser] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	me := c.Locals("metadata").(models.TokenMetaData)
+	res := h.service.UpdateUser(request, me.Id, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[userHandler][UpdateUser] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[userHandler][UpdateUser] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *userHandler) GetProfile(c *fiber.Ctx) error {
+	res := h.service.GetProfile(c.Context())
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *userHandler) UpdateProfile(c *fiber.Ctx) error {
+	request := new(requests.ProfileRequest)
+	err := c.BodyParser(request)
+	if err != nil {
+		log.Println("[userHandler][UpdateProfile] parse request body error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadateProfile()
+	if validate != nil {
+		log.Println("[userHandler][UpdateProfile] validate request body :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[userHandler][UpdateProfile] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.UpdateProfile(c.Context(), request, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[userHandler][UpdateProfile] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[userHandler][UpdateProfile] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+	return c.Status(res.StatusCode).JSON(res)
+}
+
+func (h *userHandler) UpdateLocation(c *fiber.Ctx) error {
+	request := new(requests.LocationRequest)
+	err := c.BodyParser(request)
+	if err != nil {
+		log.Println("[userHandler][UpdateLocation] parse request body error :", err)
+		return c.Status(400).JSON(h.resp.StatusBadRequest(nil, err.Error()))
+	}
+
+	validate := request.ValiadateLocation()
+	if validate != nil {
+		log.Println("[userHandler][UpdateLocation] validate request body :", helpers.JsonMinify(validate))
+		return c.Status(400).JSON(h.resp.StatusBadRequest(validate, "invalid validation"))
+	}
+
+	dbTx := h.db.Begin()
+	if dbTx.Error != nil {
+		log.Println("[userHandler][UpdateLocation] error create db transaction :", dbTx.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+
+	res := h.service.UpdateLocation(c.Context(), request, dbTx)
+	if res.StatusCode != http.StatusOK {
+		roll := dbTx.Rollback()
+		if roll.Error != nil {
+			log.Println("[userHandler][UpdateLocation] error rollback db transaction :", roll.Error)
+			return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+		}
+		return c.Status(res.StatusCode).JSON(res)
+	}
+
+	comm := helpers.CommitTx(dbTx)
+	if comm.Error != nil {
+		log.Println("[userHandler][UpdateLocation] error commit db transaction :", comm.Error)
+		return c.Status(500).JSON(h.resp.StatusServerError("something went wrong"))
+	}
+	return c.Status(res.StatusCode).JSON(res)
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"dating-app-api/utils/jwtkeys"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WellKnownHandlerInterface interface {
+	GetJWKS(c *fiber.Ctx) error
+}
+
+type wellKnownHandler struct {
+	keys *jwtkeys.KeySet
+}
+
+func NewWellKnownHandler(keys *jwtkeys.KeySet) WellKnownHandlerInterface {
+	return &wellKnownHandler{
+		keys: keys,
+	}
+}
+
+// GetJWKS answers with the bare JWKS document instead of the response
+// envelope, it is read by other services and jwt libraries.
+func (h *wellKnownHandler) GetJWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(h.keys.JWKS())
+}
@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"dating-app-api/configs"
+	"dating-app-api/entities/responses"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminVerify guards the admin endpoints with the admin api key sent in the
+// x-admin-key header.
+func AdminVerify(env *configs.EnviConfig) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		adminKey := c.Get("x-admin-key")
+		if env.AdminApiKey == "" || adminKey == "" {
+			return c.Status(401).JSON(responses.Response{
+				StatusCode: 401,
+				Message:    "admin key required",
+			})
+		}
+
+		if subtle.ConstantTimeCompare([]byte(adminKey), []byte(env.AdminApiKey)) != 1 {
+			return c.Status(401).JSON(responses.Response{
+				StatusCode: 401,
+				Message:    "invalid admin key",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+package middlewares
+
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/responses"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const ErrorCodeEntitlementRequired = "entitlement_required"
+
+// RequireEntitlement only lets the request through when the active plan of the
+// user grants the entitlement, it must be placed after UserVerify which leaves
+// the cached subscription status of the user.
+func RequireEntitlement(entitlement string) func(*fiber.Ctx) error {
+	common := responses.NewResponseAPI()
+
+	return func(c *fiber.Ctx) error {
+		status, ok := c.Locals("subscription").(models.SubscriptionStatus)
+		if !ok {
+			return AuthFailedHandler(c, "invalid meta data")
+		}
+
+		if !status.HasEntitlement(entitlement) {
+			return c.Status(http.StatusForbidden).JSON(common.StatusForbidden(map[string]string{
+				"entitlement": entitlement,
+			}, ErrorCodeEntitlementRequired, "premium subscription required"))
+		}
+
+		return c.Next()
+	}
+}
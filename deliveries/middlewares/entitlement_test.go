@@ -0,0 +1,74 @@
+package middlewares
+
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/responses"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireEntitlement(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *models.SubscriptionStatus
+		want   int
+	}{
+		{
+			name:   "entitled",
+			status: &models.SubscriptionStatus{Active: true, Entitlements: []string{models.EntitlementRewind}},
+			want:   http.StatusOK,
+		},
+		{
+			name:   "other entitlement",
+			status: &models.SubscriptionStatus{Active: true, Entitlements: []string{models.EntitlementSeeLikes}},
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "expired plan",
+			status: &models.SubscriptionStatus{Entitlements: []string{models.EntitlementRewind}},
+			want:   http.StatusForbidden,
+		},
+		{
+			name: "no subscription status",
+			want: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				if tt.status != nil {
+					c.Locals("subscription", *tt.status)
+				}
+				return c.Next()
+			}, RequireEntitlement(models.EntitlementRewind), func(c *fiber.Ctx) error {
+				return c.SendStatus(http.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil), -1)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+
+			if tt.want == http.StatusForbidden {
+				var res responses.Response
+				if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if res.ErrorCode != ErrorCodeEntitlementRequired {
+					t.Fatalf("error code = %q, want %q", res.ErrorCode, ErrorCodeEntitlementRequired)
+				}
+			}
+		})
+	}
+}
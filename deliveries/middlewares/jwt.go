@@ -4,17 +4,22 @@ import (
 	"dating-app-api/configs"
 	"dating-app-api/entities/models"
 	"dating-app-api/entities/responses"
-	"fmt"
+	"dating-app-api/repositories"
+	"dating-app-api/utils/jwtkeys"
+	"log"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-func UserVerify(conf *configs.EnviConfig) func(*fiber.Ctx) error {
+// tokenLeeway absorbs clock skew between the services validating our tokens
+const tokenLeeway = 30 * time.Second
+
+func UserVerify(conf *configs.EnviConfig, subscriptionRepo repositories.SubscriptionRepositoryInterface) func(*fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		authToken := string(c.Request().Header.Peek("Authorization"))
 		if authToken == "" {
@@ -26,61 +31,83 @@ func UserVerify(conf *configs.EnviConfig) func(*fiber.Ctx) error {
 			return AuthFailedHandler(c, "no token is headers")
 		}
 
-		token, err := verifyToken(splitToken[1], conf.JwtKey)
-		if err != nil || !token.Valid {
+		// a refresh token carries the refresh audience and is rejected here
+		claims, err := verifyToken(splitToken[1], conf.JwtKeys, conf.JwtIssuer, conf.JwtAudience)
+		if err != nil {
 			return AuthFailedHandler(c, "invalid token")
 		}
 
-		metadata := extractTokenMetadata(token)
-		if metadata.Id == "" {
+		metadata := extractTokenMetadata(claims)
+		if metadata.Id == "" || metadata.Sid == "" {
 			return AuthFailedHandler(c, "invalid meta data")
 		}
 
-		var userTokenData models.TokenMetaData
-		err = conf.Redis.RetrieveDataFromRedis(fmt.Sprintf("metaat:%v", metadata.Id), &userTokenData)
-		if err != nil {
+		tokenData, err := conf.TokenStore.RetrieveAccessToken(metadata.Id, metadata.Sid)
+		if err != nil || tokenData == nil {
 			return AuthFailedHandler(c, "invalid metadata or token expired")
 		}
+		userTokenData := *tokenData
+
+		session, err := conf.TokenStore.RetrieveSession(metadata.Id, metadata.Sid)
+		if err != nil || session == nil {
+			return AuthFailedHandler(c, "session has been revoked")
+		}
+
+		if err := TouchSession(conf, session, c.IP(), string(c.Request().Header.UserAgent())); err != nil {
+			log.Println("[middlewares][UserVerify] error touch session :", err)
+		}
+
+		// premium state follows the live subscription, not the token claims
+		status, err := retrieveSubscriptionStatus(conf, subscriptionRepo, metadata.Id)
+		if err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(responses.Response{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "something went wrong",
+			})
+		}
+		userTokenData.Verify = status.Active
 
 		c.Locals("metadata", userTokenData)
+		c.Locals("subscription", *status)
 		return c.Next()
 	}
 }
 
-func verifyToken(token, key string) (*jwt.Token, error) {
-	tokenJWT, err := jwt.Parse(token, func(tokenJWT *jwt.Token) (interface{}, error) {
-		// Make sure that the token method conform to "SigningMethodHMAC"
-		if _, ok := tokenJWT.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", tokenJWT.Header["alg"])
-		}
+// TokenClaims are the claims of access and refresh tokens, sub is the user id
+// and only refresh tokens carry rt_id.
+type TokenClaims struct {
+	Sid    string `json:"sid"`
+	RtId   string `json:"rt_id,omitempty"`
+	Verify bool   `json:"verify"`
+	jwt.RegisteredClaims
+}
 
-		return []byte(key), nil
-	})
-	if err != nil {
+// verifyToken checks the signature and the registered claims, a token of
+// another issuer or audience is invalid even when its signature is valid.
+func verifyToken(token string, keys *jwtkeys.KeySet, issuer, audience string) (*TokenClaims, error) {
+	parser := jwt.NewParser(
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(tokenLeeway),
+	)
+
+	claims := &TokenClaims{}
+	if _, err := parser.ParseWithClaims(token, claims, keys.Keyfunc); err != nil {
 		return nil, err
 	}
-	return tokenJWT, nil
-}
 
-func extractTokenMetadata(jwtToken *jwt.Token) (res models.TokenMetaData) {
-	claims, ok := jwtToken.Claims.(jwt.MapClaims)
-	if ok {
-		ID, _ := claims["id"].(string)
-		verify, _ := claims["verify"].(bool)
-
-		exp, err := strconv.ParseInt(fmt.Sprintf("%.f", claims["exp"]), 0, 64)
-		if err != nil {
-			return res
-		}
-
-		res.Id = ID
-		if claims["rt_id"] != nil {
-			res.RtId = claims["rt_id"].(string)
-		}
+	return claims, nil
+}
 
-		res.Exp = exp
-		res.Verify = verify
-		return res
+func extractTokenMetadata(claims *TokenClaims) (res models.TokenMetaData) {
+	res.Id = claims.Subject
+	res.Sid = claims.Sid
+	res.RtId = claims.RtId
+	res.Verify = claims.Verify
+	if claims.ExpiresAt != nil {
+		res.Exp = claims.ExpiresAt.Unix()
 	}
 
 	return res
@@ -96,40 +123,48 @@ func AuthFailedHandler(c *fiber.Ctx, message string) error {
 
 func GenerateToken(conf *configs.EnviConfig, data models.TokenMetaData, isRefresh bool) (token string, err error) {
 
-	jwtToken := jwt.New(jwt.SigningMethodHS256)
-	acClaims := jwtToken.Claims.(jwt.MapClaims)
-
-	jwtKey := conf.JwtKey
+	jwtKeys := conf.JwtKeys
+	audience := conf.JwtAudience
 	expTime := conf.JwtAtExpTime
 	if isRefresh {
-		jwtKey = conf.JwtRKey
+		jwtKeys = conf.JwtRKeys
+		audience = conf.JwtRefreshAudience
 		expTime = conf.JwtRtExpTime
 	}
 
 	jwtAcExpiredAt := time.Minute * time.Duration(expTime)
 	jwtRtExpiredAt := time.Minute * time.Duration(expTime)
-	// Set claims
-	acClaims["id"] = data.Id
-	acClaims["exp"] = time.Now().Add(jwtAcExpiredAt).Unix()
-	acClaims["verify"] = data.Verify
+	now := time.Now()
+	claims := TokenClaims{
+		Sid:    data.Sid,
+		Verify: data.Verify,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    conf.JwtIssuer,
+			Subject:   data.Id,
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(jwtAcExpiredAt)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
+	}
 	if isRefresh {
-		acClaims["rt_id"] = data.RtId
-		acClaims["exp"] = time.Now().Add(jwtRtExpiredAt).Unix()
-		acClaims["verify"] = data.Verify
+		claims.RtId = data.RtId
+		claims.ExpiresAt = jwt.NewNumericDate(now.Add(jwtRtExpiredAt))
 	}
 
-	token, err = jwtToken.SignedString([]byte(jwtKey))
+	token, err = jwtKeys.Sign(claims)
 	if err != nil {
 		return "", err
 	}
 
 	if isRefresh {
-		err = conf.Redis.SaveDataToRedis(fmt.Sprintf("metart:%v:%v", data.Id, acClaims["rt_id"]), data, jwtRtExpiredAt)
+		err = conf.TokenStore.SaveRefreshToken(data.Id, data.Sid, data.RtId, jwtRtExpiredAt)
 		if err != nil {
 			return "", err
 		}
 	} else {
-		err = conf.Redis.SaveDataToRedis(fmt.Sprintf("metaat:%v", data.Id), data, jwtAcExpiredAt)
+		err = conf.TokenStore.SaveAccessToken(data, jwtAcExpiredAt)
 		if err != nil {
 			return "", err
 		}
@@ -150,24 +185,29 @@ func RefreshTokenVerify(conf *configs.EnviConfig) func(*fiber.Ctx) error {
 			return AuthFailedHandler(c, "no token is headers")
 		}
 
-		token, err := verifyToken(splitToken[1], conf.JwtRKey)
-		if err != nil || !token.Valid {
+		claims, err := verifyToken(splitToken[1], conf.JwtRKeys, conf.JwtIssuer, conf.JwtRefreshAudience)
+		if err != nil {
 			return AuthFailedHandler(c, "invalid token")
 		}
 
-		metaData := extractTokenMetadata(token)
-		if metaData.Id == "" {
+		metaData := extractTokenMetadata(claims)
+		if metaData.Id == "" || metaData.Sid == "" {
 			return AuthFailedHandler(c, "invalid meta data")
 		}
 
-		var newTokenMetaData models.TokenMetaData
-		redisKey := fmt.Sprintf("metart:%v:%v", metaData.Id, metaData.RtId)
-		if err := conf.Redis.RetrieveDataFromRedis(redisKey, &newTokenMetaData); err != nil {
+		// whether the token is still the current one of its family is left
+		// to the rotation, a reused token has to revoke the session
+		family, err := conf.TokenStore.RetrieveRefreshFamily(metaData.Id, metaData.Sid)
+		if err != nil || family == nil || metaData.RtId == "" {
 			return AuthFailedHandler(c, "invalid metadata or token expired")
 		}
 
-		newTokenMetaData.RtId = metaData.RtId
-		c.Locals("metadata", newTokenMetaData)
+		session, err := conf.TokenStore.RetrieveSession(metaData.Id, metaData.Sid)
+		if err != nil || session == nil {
+			return AuthFailedHandler(c, "session has been revoked")
+		}
+
+		c.Locals("metadata", metaData)
 		return c.Next()
 	}
 }
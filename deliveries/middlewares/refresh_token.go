@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/helpers"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrRefreshTokenReused is returned for a refresh token that was already
+	// rotated, the session has to be revoked as the token may be stolen
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	// ErrRefreshTokenRotated is returned for the previous refresh token of the
+	// session while it is still in the grace period, it lost a concurrent refresh
+	ErrRefreshTokenRotated = errors.New("refresh token already rotated")
+	// ErrRefreshTokenRevoked is returned when the session has no refresh token
+	// family anymore
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+)
+
+// RefreshTokenRotator keeps one refresh token family per session, every
+// refresh swaps the presented token for a new one so each token works once.
+type RefreshTokenRotator struct {
+	conf  *configs.EnviConfig
+	clock helpers.Clock
+	grace time.Duration
+}
+
+func NewRefreshTokenRotator(conf *configs.EnviConfig, clock helpers.Clock) *RefreshTokenRotator {
+	return &RefreshTokenRotator{
+		conf:  conf,
+		clock: clock,
+		grace: time.Second * time.Duration(conf.JwtRtGraceSeconds),
+	}
+}
+
+// Rotate consumes the refresh token of meta and returns the id of the one
+// that replaces it.
+func (r *RefreshTokenRotator) Rotate(meta models.TokenMetaData) (string, error) {
+	next := uuid.NewString()
+	swapped, family, err := r.conf.TokenStore.RotateRefreshToken(meta.Id, meta.Sid, meta.RtId, next, r.clock.Now())
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case swapped:
+		return next, nil
+	case family == nil:
+		return "", ErrRefreshTokenRevoked
+	case family.Previous == meta.RtId && r.clock.Now().Sub(family.RotatedAt) < r.grace:
+		return "", ErrRefreshTokenRotated
+	}
+
+	return "", ErrRefreshTokenReused
+}
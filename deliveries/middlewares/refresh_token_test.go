@@ -0,0 +1,153 @@
+package middlewares
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/utils/tokenstore"
+	"dating-app-api/utils/tokenstore/tokenstoretest"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock only moves when the test advances it
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+const testGraceSeconds = 10
+
+// newTestRotator returns a rotator over an in memory store holding the
+// refresh token "rt-1" for session "sid-1" of user "user-1".
+func newTestRotator(t *testing.T) (*RefreshTokenRotator, *fakeClock, tokenstore.Store) {
+	t.Helper()
+
+	clock := &fakeClock{now: time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)}
+	store := tokenstoretest.NewMemoryStore(clock)
+	conf := &configs.EnviConfig{
+		JwtRtGraceSeconds: testGraceSeconds,
+		TokenStore:        store,
+	}
+
+	if err := store.SaveSession(&models.SessionModel{Id: "sid-1", UserId: "user-1"}, time.Hour); err != nil {
+		t.Fatalf("save session: %v", err)
+	}
+	if err := store.SaveRefreshToken("user-1", "sid-1", "rt-1", time.Hour); err != nil {
+		t.Fatalf("save refresh token: %v", err)
+	}
+
+	return NewRefreshTokenRotator(conf, clock), clock, store
+}
+
+func refreshMeta(rtId string) models.TokenMetaData {
+	return models.TokenMetaData{Id: "user-1", Sid: "sid-1", RtId: rtId}
+}
+
+func TestRefreshTokenRotatorRotate(t *testing.T) {
+	rotator, clock, store := newTestRotator(t)
+
+	next, err := rotator.Rotate(refreshMeta("rt-1"))
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if next == "" || next == "rt-1" {
+		t.Fatalf("rotate returned %q, want a new token id", next)
+	}
+
+	family, err := store.RetrieveRefreshFamily("user-1", "sid-1")
+	if err != nil {
+		t.Fatalf("retrieve refresh family: %v", err)
+	}
+	if family.Current != next || family.Previous != "rt-1" || !family.RotatedAt.Equal(clock.Now()) {
+		t.Fatalf("family = %+v, want current %q previous rt-1 rotated at %v", family, next, clock.Now())
+	}
+
+	// the new token rotates like the first one
+	clock.Advance(time.Minute)
+	after, err := rotator.Rotate(refreshMeta(next))
+	if err != nil {
+		t.Fatalf("rotate new token: %v", err)
+	}
+	if after == next {
+		t.Fatalf("rotate new token returned the same id %q", after)
+	}
+}
+
+func TestRefreshTokenRotatorConcurrentRefresh(t *testing.T) {
+	rotator, clock, _ := newTestRotator(t)
+
+	const clients = 8
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, clients)
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = rotator.Rotate(refreshMeta("rt-1"))
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	rotated := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			rotated++
+		case !errors.Is(err, ErrRefreshTokenRotated):
+			t.Fatalf("concurrent refresh error = %v, want %v", err, ErrRefreshTokenRotated)
+		}
+	}
+	if rotated != 1 {
+		t.Fatalf("%d refreshes rotated the token, want exactly 1", rotated)
+	}
+
+	// the token is still within the grace window until it ends
+	clock.Advance(testGraceSeconds*time.Second - time.Millisecond)
+	if _, err := rotator.Rotate(refreshMeta("rt-1")); !errors.Is(err, ErrRefreshTokenRotated) {
+		t.Fatalf("refresh at the end of the grace window error = %v, want %v", err, ErrRefreshTokenRotated)
+	}
+}
+
+func TestRefreshTokenRotatorReuseAfterGrace(t *testing.T) {
+	rotator, clock, store := newTestRotator(t)
+
+	next, err := rotator.Rotate(refreshMeta("rt-1"))
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+
+	clock.Advance(testGraceSeconds * time.Second)
+	if _, err := rotator.Rotate(refreshMeta("rt-1")); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("refresh after the grace window error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+
+	// a token older than the previous one is a reuse at any time
+	if _, err := rotator.Rotate(refreshMeta("rt-0")); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("refresh with an unknown token error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+
+	// once the session is revoked no token of the family rotates anymore
+	if err := store.Revoke("user-1", "sid-1"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := rotator.Rotate(refreshMeta(next)); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Fatalf("refresh after revoke error = %v, want %v", err, ErrRefreshTokenRevoked)
+	}
+}
@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/helpers"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sessionTouchInterval keeps the last use of a session from being written on
+// every request
+const sessionTouchInterval = time.Minute
+
+func sessionTTL(conf *configs.EnviConfig) time.Duration {
+	return time.Minute * time.Duration(conf.JwtRtExpTime)
+}
+
+// CreateSession starts a new session for the device, the id is the sid claim
+// of every token issued for it.
+func CreateSession(conf *configs.EnviConfig, session *models.SessionModel) error {
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	session.Id = uuid.NewString()
+	session.CreatedAt = now
+	session.LastUsedAt = now
+
+	return SaveSession(conf, session)
+}
+
+// SaveSession stores the session for the lifetime of a refresh token.
+func SaveSession(conf *configs.EnviConfig, session *models.SessionModel) error {
+	return conf.TokenStore.SaveSession(session, sessionTTL(conf))
+}
+
+// RenewSession stores the session for the lifetime of a refresh token again,
+// it is called on every refresh so an active device stays signed in. A
+// revoked session is not brought back, tokenstore.ErrSessionRevoked is
+// returned instead.
+func RenewSession(conf *configs.EnviConfig, session *models.SessionModel) error {
+	return conf.TokenStore.RenewSession(session, sessionTTL(conf))
+}
+
+// RetrieveSessions lists the sessions of the user, most recently used first.
+func RetrieveSessions(conf *configs.EnviConfig, userId string) ([]models.SessionModel, error) {
+	ids, err := conf.TokenStore.RetrieveSessionIds(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]models.SessionModel, 0, len(ids))
+	for _, id := range ids {
+		session, err := conf.TokenStore.RetrieveSession(userId, id)
+		if err != nil {
+			return nil, err
+		}
+
+		// the session expired on its own, only the index still knows it
+		if session == nil {
+			if err := conf.TokenStore.Revoke(userId, id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		sessions = append(sessions, *session)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt > sessions[j].LastUsedAt
+	})
+
+	return sessions, nil
+}
+
+// TouchSession records the last use of the session, writes are skipped while
+// the previous one is still recent.
+func TouchSession(conf *configs.EnviConfig, session *models.SessionModel, ip, userAgent string) error {
+	lastUsed, err := helpers.ParseDateTime(session.LastUsedAt)
+	if err == nil && time.Since(lastUsed) < sessionTouchInterval && session.Ip == ip && session.UserAgent == userAgent {
+		return nil
+	}
+
+	session.LastUsedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	session.Ip = ip
+	session.UserAgent = userAgent
+	return conf.TokenStore.UpdateSession(session)
+}
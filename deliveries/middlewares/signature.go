@@ -35,7 +35,7 @@ func VerifySignature(env *configs.EnviConfig) func(*fiber.Ctx) error {
 			})
 		}
 		body := string(c.Body())
-		generatedSignature := generateSignature(env.ApiKey, body, timeStamp)
+		generatedSignature := GenerateSignature(env.ApiKey, body, timeStamp)
 		if generatedSignature != signature {
 			return c.Status(401).JSON(responses.Response{
 				StatusCode: 401,
@@ -46,7 +46,7 @@ func VerifySignature(env *configs.EnviConfig) func(*fiber.Ctx) error {
 	}
 }
 
-func generateSignature(apiKey, body, time string) string {
+func GenerateSignature(apiKey, body, time string) string {
 	bodyToEnc := body + ":" + time
 
 	h := hmac.New(sha256.New, []byte(apiKey))
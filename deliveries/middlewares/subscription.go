@@ -0,0 +1,66 @@
+package middlewares
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/helpers"
+	"dating-app-api/repositories"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const subscriptionCacheDuration = 5 * time.Minute
+
+func SubscriptionStatusKey(userId string) string {
+	return "subscription:" + userId
+}
+
+// retrieveSubscriptionStatus resolves the live subscription of the user, the
+// result is cached shortly in redis and never outlives the subscription end.
+func retrieveSubscriptionStatus(conf *configs.EnviConfig, subscriptionRepo repositories.SubscriptionRepositoryInterface, userId string) (*models.SubscriptionStatus, error) {
+	var status models.SubscriptionStatus
+	err := conf.Redis.RetrieveDataFromRedis(SubscriptionStatusKey(userId), &status)
+	if err == nil {
+		return &status, nil
+	}
+
+	if err.Error() != redis.Nil.Error() {
+		return nil, err
+	}
+
+	subscription, err := subscriptionRepo.GetActiveSubscription(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	status = models.SubscriptionStatus{
+		Entitlements: []string{},
+	}
+	duration := subscriptionCacheDuration
+
+	if subscription != nil {
+		status.Active = true
+		status.EndAt = subscription.EndAt
+		if subscription.Plan != nil {
+			status.PlanCode = subscription.Plan.Code
+			status.Entitlements = subscription.Plan.Entitlements
+		}
+
+		endAt, err := helpers.ParseDateTime(subscription.EndAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if untilEnd := time.Until(endAt); untilEnd < duration {
+			duration = untilEnd
+		}
+	}
+
+	err = conf.Redis.SaveDataToRedis(SubscriptionStatusKey(userId), status, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
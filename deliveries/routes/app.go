@@ -10,4 +10,12 @@ import (
 func Build(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
 	BuildUserRoute(route, env, db)
 	BuidAuthRoute(route, env, db)
+	BuildSwipeRoute(route, env, db)
+	BuildMatchRoute(route, env, db)
+	BuildSubscriptionRoute(route, env, db)
+	BuildPhotoRoute(route, env, db)
+	BuildModerationRoute(route, env, db)
+	BuildNotificationRoute(route, env, db)
+	BuildPreferenceRoute(route, env, db)
+	BuildBoostRoute(route, env, db)
 }
@@ -5,6 +5,7 @@ import (
 	"dating-app-api/deliveries/handlers"
 	"dating-app-api/deliveries/middlewares"
 	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
 	"dating-app-api/repositories"
 	"dating-app-api/services"
 
@@ -14,15 +15,20 @@ import (
 
 func BuidAuthRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
 	common := responses.NewResponseAPI()
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
 	userRepo := repositories.NewUserRepository(db)
-	authService := services.NewAuthService(userRepo, *common, env.Redis, &env)
+	rotator := middlewares.NewRefreshTokenRotator(&env, helpers.SystemClock{})
+	authService := services.NewAuthService(userRepo, *common, env.Redis, &env, rotator)
 	authHandler := handlers.NewAuthHandler(authService, *common, db)
 
-	userVerify := middlewares.UserVerify(&env)
+	userVerify := middlewares.UserVerify(&env, subscriptionRepo)
 	userRtVerify := middlewares.RefreshTokenVerify(&env)
 	signatureVerify := middlewares.VerifySignature(&env)
 
 	route.Post("/auth/login", signatureVerify, authHandler.Login)
 	route.Post("/auth/logout", userVerify, authHandler.LogOut)
 	route.Post("/auth/refresh-token", userRtVerify, authHandler.RefreshToken)
+	route.Get("/auth/sessions", userVerify, authHandler.GetListSession)
+	route.Delete("/auth/sessions", userVerify, authHandler.RevokeAllSessions)
+	route.Delete("/auth/sessions/:id", userVerify, authHandler.RevokeSession)
 }
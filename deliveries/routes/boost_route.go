@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func BuildBoostRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
+	common := responses.NewResponseAPI()
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
+	boostRepo := repositories.NewBoostRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	boostService := services.NewBoostService(boostRepo, userRepo, *common, env.Redis, &env)
+	boostHandler := handlers.NewBoostHandler(boostService, *common, db)
+
+	userVerify := middlewares.UserVerify(&env, subscriptionRepo)
+	adminVerify := middlewares.AdminVerify(&env)
+
+	route.Post("/boost", userVerify, boostHandler.Boost)
+	route.Get("/boost/credits", userVerify, boostHandler.GetCredit)
+	route.Get("/boost/:id", userVerify, boostHandler.GetDetail)
+	route.Post("/admin/boost/credits", adminVerify, boostHandler.GrantCredit)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func BuildMatchRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
+	common := responses.NewResponseAPI()
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
+	matchRepo := repositories.NewMatchRepository(db)
+	swipeRepo := repositories.NewSwipeRepository(db)
+	matchService := services.NewMatchService(matchRepo, swipeRepo, *common, env.Redis, &env)
+	matchHandler := handlers.NewMatchHandler(matchService, *common, db)
+
+	userVerify := middlewares.UserVerify(&env, subscriptionRepo)
+
+	route.Get("/matches", userVerify, matchHandler.GetListMatch)
+	route.Delete("/matches/:id", userVerify, matchHandler.Unmatch)
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func BuildModerationRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
+	common := responses.NewResponseAPI()
+	photoRepo := repositories.NewPhotoRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	moderationService := services.NewModerationService(photoRepo, notificationRepo, *common, env.Redis, &env)
+	moderationHandler := handlers.NewModerationHandler(moderationService, *common, db)
+
+	adminVerify := middlewares.AdminVerify(&env)
+
+	route.Get("/admin/moderation/photos", adminVerify, moderationHandler.GetListQueue)
+	route.Put("/admin/moderation/photos/:id/approve", adminVerify, moderationHandler.ApprovePhoto)
+	route.Put("/admin/moderation/photos/:id/reject", adminVerify, moderationHandler.RejectPhoto)
+}
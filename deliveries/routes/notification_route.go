@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func BuildNotificationRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
+	common := responses.NewResponseAPI()
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	notificationService := services.NewNotificationService(notificationRepo, *common, env.Redis, &env)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, *common, db)
+
+	userVerify := middlewares.UserVerify(&env, subscriptionRepo)
+
+	route.Get("/user/notifications", userVerify, notificationHandler.GetListNotification)
+	route.Put("/user/notifications/:id/read", userVerify, notificationHandler.ReadNotification)
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func BuildPhotoRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
+	common := responses.NewResponseAPI()
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
+	photoRepo := repositories.NewPhotoRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	photoService := services.NewPhotoService(photoRepo, notificationRepo, *common, env.Redis, &env, db)
+	photoHandler := handlers.NewPhotoHandler(photoService, *common, db)
+
+	userVerify := middlewares.UserVerify(&env, subscriptionRepo)
+
+	route.Post("/user/photos", userVerify, photoHandler.UploadPhoto)
+	route.Get("/user/photos", userVerify, photoHandler.GetListPhoto)
+	route.Put("/user/photos/order", userVerify, photoHandler.ReorderPhoto)
+	route.Put("/user/photos/:id/primary", userVerify, photoHandler.SetPrimaryPhoto)
+	route.Delete("/user/photos/:id", userVerify, photoHandler.DeletePhoto)
+
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func BuildPreferenceRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
+	common := responses.NewResponseAPI()
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
+	preferenceRepo := repositories.NewPreferenceRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	preferenceService := services.NewPreferenceService(preferenceRepo, userRepo, *common, env.Redis, &env)
+	preferenceHandler := handlers.NewPreferenceHandler(preferenceService, *common, db)
+
+	userVerify := middlewares.UserVerify(&env, subscriptionRepo)
+
+	route.Get("/user/preferences", userVerify, preferenceHandler.GetPreference)
+	route.Put("/user/preferences", userVerify, preferenceHandler.UpdatePreference)
+}
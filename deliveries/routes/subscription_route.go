@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func BuildSubscriptionRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
+	common := responses.NewResponseAPI()
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
+	subscriptionService := services.NewSubscriptionService(subscriptionRepo, *common, env.Redis, &env, db)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService, *common)
+
+	userVerify := middlewares.UserVerify(&env, subscriptionRepo)
+
+	route.Get("/plans", userVerify, subscriptionHandler.GetListPlan)
+	route.Get("/user/subscription", userVerify, subscriptionHandler.GetSubscription)
+
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func BuildSwipeRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
+	common := responses.NewResponseAPI()
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	swipeRepo := repositories.NewSwipeRepository(db)
+	matchRepo := repositories.NewMatchRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	swipeService := services.NewSwipeService(swipeRepo, userRepo, matchRepo, notificationRepo, env.RatingUpdater, *common, env.Redis, &env)
+	swipeHandler := handlers.NewSwipeHandler(swipeService, *common, db)
+
+	userVerify := middlewares.UserVerify(&env, subscriptionRepo)
+
+	route.Post("/swipe", userVerify, swipeHandler.Swipe)
+	// without free rewinds a day the rewind is a premium only endpoint
+	rewind := []fiber.Handler{userVerify}
+	if env.SwipeRewindDailyLimit == 0 {
+		rewind = append(rewind, middlewares.RequireEntitlement(models.EntitlementRewind))
+	}
+	route.Post("/swipe/rewind", append(rewind, swipeHandler.Rewind)...)
+	route.Get("/swipe/quota", userVerify, swipeHandler.GetQuota)
+	route.Get("/likes/received", userVerify, swipeHandler.GetListReceivedLikes)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// BuildUploadRoute serves the files of the local storage driver, it is mounted
+// on the app root like the storage base url.
+func BuildUploadRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
+	common := responses.NewResponseAPI()
+	photoRepo := repositories.NewPhotoRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	photoService := services.NewPhotoService(photoRepo, notificationRepo, *common, env.Redis, &env, db)
+	uploadHandler := handlers.NewUploadHandler(photoService, *common, env.StorageLocalPath)
+
+	adminVerify := middlewares.AdminVerify(&env)
+
+	route.Get("/uploads/photos/:userId/:photoId/:file", uploadHandler.GetPhotoFile)
+	route.Get("/admin/uploads/photos/:userId/:photoId/:file", adminVerify, uploadHandler.GetModerationPhotoFile)
+}
@@ -14,17 +14,27 @@ import (
 
 func BuildUserRoute(route fiber.Router, env configs.EnviConfig, db *gorm.DB) {
 	common := responses.NewResponseAPI()
+	subscriptionRepo := repositories.NewSubscriptionRepository(db)
 	userRepo := repositories.NewUserRepository(db)
-	userService := services.NewUserService(userRepo, *common, env.Redis, &env)
+	paymentRepo := repositories.NewPaymentRepository(db)
+	preferenceRepo := repositories.NewPreferenceRepository(db)
+	boostRepo := repositories.NewBoostRepository(db)
+	userService := services.NewUserService(userRepo, paymentRepo, subscriptionRepo, preferenceRepo, boostRepo, *common, env.Redis, &env)
 	userHandler := handlers.NewUserHandler(userService, *common, db)
 
-	userVerify := middlewares.UserVerify(&env)
+	userVerify := middlewares.UserVerify(&env, subscriptionRepo)
 	signatureVerify := middlewares.VerifySignature(&env)
 
 	route.Post("/user/register", signatureVerify, userHandler.RegisterUser)
 	route.Post("/user/verify", signatureVerify, userHandler.VerifyUser)
+	route.Post("/user/payment", userVerify, userHandler.CreatePayment)
 	route.Post("/user/check-username", signatureVerify, userHandler.CheckUsername)
 	route.Put("/user/change-password", userVerify, userHandler.ChangePassword)
 	route.Get("/user/detail/:id", userVerify, userHandler.GetDetailUser)
 	route.Get("/user/me", userVerify, userHandler.GetMe)
+	route.Put("/user/me", userVerify, userHandler.UpdateUser)
+	route.Get("/user/profile", userVerify, userHandler.GetProfile)
+	route.Put("/user/profile", userVerify, userHandler.UpdateProfile)
+	route.Put("/user/location", userVerify, userHandler.UpdateLocation)
+	route.Get("/discover", userVerify, userHandler.Discover)
 }
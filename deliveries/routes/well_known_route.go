@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/handlers"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BuildWellKnownRoute is mounted on the app root, outside of the versioned api.
+func BuildWellKnownRoute(route fiber.Router, env configs.EnviConfig) {
+	wellKnownHandler := handlers.NewWellKnownHandler(env.JwtKeys)
+
+	route.Get("/.well-known/jwks.json", wellKnownHandler.GetJWKS)
+}
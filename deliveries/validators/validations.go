@@ -1,9 +1,11 @@
 package validators
 
 import (
+	"dating-app-api/entities/models"
 	"errors"
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/thedevsaddam/govalidator"
 )
@@ -40,6 +42,8 @@ func ValidatePassword(password string) error {
 	return nil
 }
 
+const MinimumAge = 18
+
 const (
 	Numeric string = "^-?[0-9]+$"
 	Key     string = "^[-a-zA-Z0-9_-]+$"
@@ -83,6 +87,24 @@ func AddValidatorLibs() {
 			return err
 		}
 
+		return nil
+	})
+	govalidator.AddCustomRule("adult_libs", func(field string, rule string, message string, value interface{}) error {
+		str := toString(value)
+		if str == "" {
+			return nil
+		}
+
+		err := fmt.Errorf("the %s field must be at least %d years ago", field, MinimumAge)
+		if message != "" {
+			err = errors.New(message)
+		}
+
+		birthdate, parseErr := time.Parse("2006-01-02", str)
+		if parseErr != nil || models.AgeAt(birthdate, time.Now()) < MinimumAge {
+			return err
+		}
+
 		return nil
 	})
 }
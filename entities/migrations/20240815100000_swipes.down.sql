@@ -0,0 +1,5 @@
+begin;
+
+drop table swipes;
+
+commit;
\ No newline at end of file
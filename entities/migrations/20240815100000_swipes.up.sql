@@ -0,0 +1,15 @@
+begin;
+
+CREATE TABLE IF NOT EXISTS swipes
+(
+    id            uuid            NOT NULL default uuid_generate_v4() primary key,
+    swiper_id     uuid            NOT NULL references users (id),
+    target_id     uuid            NOT NULL references users (id),
+    direction     varchar(10)     NOT NULL,
+    created_at    timestamp       NOT NULL,
+    CONSTRAINT uq_swipes_swiper_target UNIQUE (swiper_id, target_id)
+);
+CREATE INDEX IF NOT EXISTS idx_swipes_target_direction ON swipes (target_id, direction);
+
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+drop table matches;
+
+commit;
\ No newline at end of file
@@ -0,0 +1,15 @@
+begin;
+
+CREATE TABLE IF NOT EXISTS matches
+(
+    id            uuid            NOT NULL default uuid_generate_v4() primary key,
+    user_one_id   uuid            NOT NULL references users (id),
+    user_two_id   uuid            NOT NULL references users (id),
+    created_at    timestamp       NOT NULL,
+    deleted_at    timestamp       NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_matches_users ON matches (user_one_id, user_two_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_matches_user_two_id ON matches (user_two_id);
+
+
+commit;
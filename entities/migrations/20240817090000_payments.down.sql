@@ -0,0 +1,5 @@
+begin;
+
+drop table payments;
+
+commit;
\ No newline at end of file
@@ -0,0 +1,19 @@
+begin;
+
+CREATE TABLE IF NOT EXISTS payments
+(
+    id            uuid            NOT NULL default uuid_generate_v4() primary key,
+    user_id       uuid            NOT NULL references users (id),
+    va_number     varchar(16)     NOT NULL,
+    amount        numeric(15, 2)  NOT NULL,
+    status        varchar(20)     NOT NULL,
+    expired_at    timestamp       NOT NULL,
+    paid_at       timestamp       NULL,
+    created_at    timestamp       NOT NULL,
+    updated_at    timestamp       NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_payments_va_number_pending ON payments (va_number) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_payments_user_id ON payments (user_id);
+
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+drop table payment_events;
+drop table payment_transactions;
+
+commit;
\ No newline at end of file
@@ -0,0 +1,35 @@
+begin;
+
+CREATE TABLE IF NOT EXISTS payment_transactions
+(
+    id              uuid            NOT NULL default uuid_generate_v4() primary key,
+    transaction_id  varchar(64)     NOT NULL,
+    payment_id      uuid            NULL references payments (id),
+    va_number       varchar(16)     NOT NULL,
+    amount          numeric(15, 2)  NOT NULL,
+    response        text            NULL,
+    created_at      timestamp       NOT NULL,
+    updated_at      timestamp       NULL,
+    CONSTRAINT uq_payment_transactions_transaction_id UNIQUE (transaction_id)
+);
+
+CREATE TABLE IF NOT EXISTS payment_events
+(
+    id              uuid            NOT NULL default uuid_generate_v4() primary key,
+    payment_id      uuid            NULL references payments (id),
+    transaction_id  varchar(64)     NOT NULL,
+    from_status     varchar(20)     NULL,
+    to_status       varchar(20)     NOT NULL,
+    amount          numeric(15, 2)  NOT NULL,
+    note            text            NULL,
+    created_at      timestamp       NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_payment_events_payment_id ON payment_events (payment_id);
+CREATE INDEX IF NOT EXISTS idx_payment_events_transaction_id ON payment_events (transaction_id);
+
+-- payment events are an audit trail, rows can only be appended
+CREATE OR REPLACE RULE payment_events_no_update AS ON UPDATE TO payment_events DO INSTEAD NOTHING;
+CREATE OR REPLACE RULE payment_events_no_delete AS ON DELETE TO payment_events DO INSTEAD NOTHING;
+
+
+commit;
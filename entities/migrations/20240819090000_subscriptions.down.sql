@@ -0,0 +1,7 @@
+begin;
+
+alter table payments drop column plan_id;
+drop table subscriptions;
+drop table plans;
+
+commit;
\ No newline at end of file
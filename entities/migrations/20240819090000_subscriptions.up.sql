@@ -0,0 +1,40 @@
+begin;
+
+CREATE TABLE IF NOT EXISTS plans
+(
+    id              uuid            NOT NULL default uuid_generate_v4() primary key,
+    code            varchar(20)     NOT NULL,
+    name            varchar(50)     NOT NULL,
+    price           numeric(15, 2)  NOT NULL,
+    duration_days   integer         NOT NULL,
+    entitlements    text[]          NOT NULL DEFAULT '{}',
+    created_at      timestamp       NOT NULL,
+    updated_at      timestamp       NULL,
+    CONSTRAINT uq_plans_code UNIQUE (code)
+);
+
+INSERT INTO plans (code, name, price, duration_days, entitlements, created_at)
+VALUES ('monthly', 'Monthly', 50000, 30, '{unlimited_swipes,see_likes,rewind}', now()),
+       ('quarterly', 'Quarterly', 135000, 90, '{unlimited_swipes,see_likes,rewind}', now()),
+       ('yearly', 'Yearly', 480000, 365, '{unlimited_swipes,see_likes,rewind}', now())
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS subscriptions
+(
+    id            uuid            NOT NULL default uuid_generate_v4() primary key,
+    user_id       uuid            NOT NULL references users (id),
+    plan_id       uuid            NOT NULL references plans (id),
+    payment_id    uuid            NULL references payments (id),
+    status        varchar(20)     NOT NULL,
+    start_at      timestamp       NOT NULL,
+    end_at        timestamp       NOT NULL,
+    created_at    timestamp       NOT NULL,
+    updated_at    timestamp       NULL
+);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_id_end_at ON subscriptions (user_id, end_at);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_status_end_at ON subscriptions (status, end_at);
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS plan_id uuid NULL references plans (id);
+
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+drop table profiles;
+
+commit;
\ No newline at end of file
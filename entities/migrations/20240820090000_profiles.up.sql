@@ -0,0 +1,21 @@
+begin;
+
+CREATE TABLE IF NOT EXISTS profiles
+(
+    user_id         uuid            NOT NULL primary key references users (id),
+    display_name    varchar(50)     NOT NULL,
+    birthdate       date            NOT NULL,
+    gender          varchar(20)     NOT NULL,
+    interested_in   varchar(20)     NOT NULL,
+    bio             text            NULL,
+    job             varchar(100)    NULL,
+    education       varchar(100)    NULL,
+    height          integer         NULL,
+    location        varchar(100)    NULL,
+    created_at      timestamp       NOT NULL,
+    updated_at      timestamp       NULL
+);
+CREATE INDEX IF NOT EXISTS idx_profiles_gender_birthdate ON profiles (gender, birthdate);
+
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+drop table photos;
+
+commit;
\ No newline at end of file
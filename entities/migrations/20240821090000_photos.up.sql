@@ -0,0 +1,18 @@
+begin;
+
+CREATE TABLE IF NOT EXISTS photos
+(
+    id            uuid            NOT NULL default uuid_generate_v4() primary key,
+    user_id       uuid            NOT NULL references users (id),
+    storage_key   text            NOT NULL,
+    url           text            NOT NULL,
+    position      integer         NOT NULL,
+    is_primary    boolean         NOT NULL DEFAULT 'false',
+    created_at    timestamp       NOT NULL,
+    updated_at    timestamp       NULL
+);
+CREATE INDEX IF NOT EXISTS idx_photos_user_id_position ON photos (user_id, position);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_photos_user_id_primary ON photos (user_id) WHERE is_primary;
+
+
+commit;
@@ -0,0 +1,12 @@
+begin;
+
+ALTER TABLE photos ALTER COLUMN url DROP DEFAULT;
+ALTER TABLE photos DROP COLUMN IF EXISTS height;
+ALTER TABLE photos DROP COLUMN IF EXISTS width;
+ALTER TABLE photos DROP COLUMN IF EXISTS blurhash;
+ALTER TABLE photos DROP COLUMN IF EXISTS medium_url;
+ALTER TABLE photos DROP COLUMN IF EXISTS thumbnail_url;
+ALTER TABLE photos DROP COLUMN IF EXISTS rejected_reason;
+ALTER TABLE photos DROP COLUMN IF EXISTS status;
+
+commit;
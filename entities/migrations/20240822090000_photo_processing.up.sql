@@ -0,0 +1,13 @@
+begin;
+
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS status varchar(16) NOT NULL DEFAULT 'ready';
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS rejected_reason text NULL;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS thumbnail_url text NOT NULL DEFAULT '';
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS medium_url text NOT NULL DEFAULT '';
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS blurhash varchar(64) NULL;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS width integer NULL;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS height integer NULL;
+ALTER TABLE photos ALTER COLUMN url SET DEFAULT '';
+
+
+commit;
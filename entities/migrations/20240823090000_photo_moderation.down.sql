@@ -0,0 +1,10 @@
+begin;
+
+drop table notifications;
+DROP INDEX IF EXISTS idx_photos_moderation_queue;
+ALTER TABLE photos DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE photos DROP COLUMN IF EXISTS moderated_by;
+ALTER TABLE photos DROP COLUMN IF EXISTS moderation_reason;
+ALTER TABLE photos DROP COLUMN IF EXISTS moderation_status;
+
+commit;
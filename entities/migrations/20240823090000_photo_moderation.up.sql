@@ -0,0 +1,25 @@
+begin;
+
+-- photos uploaded before moderation existed stay visible
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS moderation_status varchar(16) NOT NULL DEFAULT 'approved';
+ALTER TABLE photos ALTER COLUMN moderation_status SET DEFAULT 'pending';
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS moderation_reason text NULL;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS moderated_by varchar(32) NULL;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS moderated_at timestamp NULL;
+CREATE INDEX IF NOT EXISTS idx_photos_moderation_queue ON photos (created_at) WHERE status = 'ready' AND moderation_status = 'pending';
+
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id            uuid            NOT NULL default uuid_generate_v4() primary key,
+    user_id       uuid            NOT NULL references users (id),
+    type          varchar(32)     NOT NULL,
+    title         varchar(255)    NOT NULL,
+    message       text            NOT NULL,
+    reference_id  uuid            NULL,
+    read_at       timestamp       NULL,
+    created_at    timestamp       NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_created_at ON notifications (user_id, created_at);
+
+
+commit;
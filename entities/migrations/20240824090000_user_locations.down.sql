@@ -0,0 +1,5 @@
+begin;
+
+drop table user_locations;
+
+commit;
\ No newline at end of file
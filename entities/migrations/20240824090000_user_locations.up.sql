@@ -0,0 +1,17 @@
+begin;
+
+create extension if not exists cube;
+create extension if not exists earthdistance;
+
+CREATE TABLE IF NOT EXISTS user_locations
+(
+    user_id       uuid                NOT NULL primary key references users (id),
+    latitude      double precision    NOT NULL,
+    longitude     double precision    NOT NULL,
+    created_at    timestamp           NOT NULL,
+    updated_at    timestamp           NULL
+);
+CREATE INDEX IF NOT EXISTS idx_user_locations_earth ON user_locations USING gist (ll_to_earth(latitude, longitude));
+
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+drop table preferences;
+
+commit;
\ No newline at end of file
@@ -0,0 +1,28 @@
+begin;
+
+CREATE TABLE IF NOT EXISTS preferences
+(
+    user_id         uuid            NOT NULL primary key references users (id),
+    min_age         integer         NOT NULL,
+    max_age         integer         NOT NULL,
+    genders         text[]          NOT NULL,
+    max_distance    integer         NULL,
+    min_height      integer         NULL,
+    max_height      integer         NULL,
+    require_photos  boolean         NOT NULL DEFAULT 'false',
+    created_at      timestamp       NOT NULL,
+    updated_at      timestamp       NULL
+);
+
+-- users who already have a profile get the same defaults as a first read
+INSERT INTO preferences (user_id, min_age, max_age, genders, created_at)
+SELECT user_id,
+       GREATEST(18, LEAST(99, age - 5)),
+       GREATEST(18, LEAST(99, age + 5)),
+       CASE WHEN interested_in = 'everyone' THEN ARRAY['male', 'female', 'non_binary'] ELSE ARRAY[interested_in] END,
+       now() at time zone 'utc'
+FROM (SELECT user_id, interested_in, date_part('year', age(birthdate))::integer AS age FROM profiles) p
+ON CONFLICT (user_id) DO NOTHING;
+
+
+commit;
@@ -0,0 +1,7 @@
+begin;
+
+ALTER TABLE profiles DROP COLUMN IF EXISTS interests;
+DROP INDEX IF EXISTS idx_users_last_active_at;
+ALTER TABLE users DROP COLUMN IF EXISTS last_active_at;
+
+commit;
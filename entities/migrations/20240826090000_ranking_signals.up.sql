@@ -0,0 +1,11 @@
+begin;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_active_at timestamp NULL;
+UPDATE users SET last_active_at = created_at WHERE last_active_at IS NULL;
+ALTER TABLE users ALTER COLUMN last_active_at SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_users_last_active_at ON users (last_active_at);
+
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS interests text[] NOT NULL DEFAULT '{}';
+
+
+commit;
@@ -0,0 +1,14 @@
+begin;
+
+CREATE TABLE IF NOT EXISTS user_ratings
+(
+    user_id         uuid                NOT NULL primary key references users (id),
+    rating          double precision    NOT NULL DEFAULT 1000,
+    swipes_count    integer             NOT NULL DEFAULT 0,
+    created_at      timestamp           NOT NULL,
+    updated_at      timestamp           NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_ratings_rating ON user_ratings (rating);
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+DROP INDEX IF EXISTS idx_swipes_swiper_created_at;
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+CREATE INDEX IF NOT EXISTS idx_swipes_swiper_created_at ON swipes (swiper_id, created_at);
+
+commit;
@@ -0,0 +1,6 @@
+begin;
+
+DROP INDEX IF EXISTS idx_swipes_target_super;
+ALTER TABLE swipes DROP COLUMN IF EXISTS is_super;
+
+commit;
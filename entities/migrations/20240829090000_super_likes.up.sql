@@ -0,0 +1,6 @@
+begin;
+
+ALTER TABLE swipes ADD COLUMN IF NOT EXISTS is_super boolean NOT NULL DEFAULT 'false';
+CREATE INDEX IF NOT EXISTS idx_swipes_target_super ON swipes (target_id, swiper_id) WHERE is_super;
+
+commit;
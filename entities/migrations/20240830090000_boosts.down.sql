@@ -0,0 +1,8 @@
+begin;
+
+drop table boost_impressions;
+drop table boosts;
+drop table boost_credits;
+ALTER TABLE plans DROP COLUMN IF EXISTS boost_credits;
+
+commit;
@@ -0,0 +1,36 @@
+begin;
+
+ALTER TABLE plans ADD COLUMN IF NOT EXISTS boost_credits integer NOT NULL DEFAULT 0;
+UPDATE plans SET boost_credits = 1 WHERE code = 'monthly';
+UPDATE plans SET boost_credits = 3 WHERE code = 'quarterly';
+UPDATE plans SET boost_credits = 12 WHERE code = 'yearly';
+
+CREATE TABLE IF NOT EXISTS boost_credits
+(
+    user_id         uuid            NOT NULL primary key references users (id),
+    balance         integer         NOT NULL DEFAULT 0,
+    created_at      timestamp       NOT NULL,
+    updated_at      timestamp       NULL,
+    CONSTRAINT chk_boost_credits_balance CHECK (balance >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS boosts
+(
+    id              uuid                NOT NULL default uuid_generate_v4() primary key,
+    user_id         uuid                NOT NULL references users (id),
+    multiplier      double precision    NOT NULL,
+    start_at        timestamp           NOT NULL,
+    end_at          timestamp           NOT NULL,
+    created_at      timestamp           NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_boosts_user_id_end_at ON boosts (user_id, end_at);
+
+CREATE TABLE IF NOT EXISTS boost_impressions
+(
+    boost_id        uuid            NOT NULL references boosts (id),
+    viewer_id       uuid            NOT NULL references users (id),
+    created_at      timestamp       NOT NULL,
+    primary key (boost_id, viewer_id)
+);
+
+commit;
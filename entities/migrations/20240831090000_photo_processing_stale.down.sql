@@ -0,0 +1,5 @@
+begin;
+
+DROP INDEX IF EXISTS idx_photos_processing_created_at;
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+CREATE INDEX IF NOT EXISTS idx_photos_processing_created_at ON photos (created_at) WHERE status = 'processing';
+
+commit;
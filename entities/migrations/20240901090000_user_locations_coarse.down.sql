@@ -0,0 +1,5 @@
+begin;
+
+-- the exact coordinates are gone, nothing to restore
+
+commit;
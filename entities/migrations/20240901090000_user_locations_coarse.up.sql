@@ -0,0 +1,5 @@
+begin;
+
+UPDATE user_locations SET latitude = round(latitude::numeric, 2), longitude = round(longitude::numeric, 2);
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+ALTER TABLE matches DROP COLUMN IF EXISTS swipe_id;
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS swipe_id uuid NULL;
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+ALTER TABLE swipes DROP COLUMN IF EXISTS previous_direction;
+
+commit;
@@ -0,0 +1,5 @@
+begin;
+
+ALTER TABLE swipes ADD COLUMN IF NOT EXISTS previous_direction varchar NULL;
+
+commit;
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BoostModel struct {
+	Id         string  `json:"id"`
+	UserId     string  `json:"user_id"`
+	Multiplier float64 `json:"multiplier"`
+	StartAt    string  `json:"start_at"`
+	EndAt      string  `json:"end_at"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+func (c BoostModel) TableName() string {
+	return "boosts"
+}
+
+func (l *BoostModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.Id = uuid.NewString()
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+type BoostCreditModel struct {
+	UserId    string  `json:"user_id" gorm:"primaryKey"`
+	Balance   int     `json:"balance"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt *string `json:"updated_at"`
+}
+
+func (c BoostCreditModel) TableName() string {
+	return "boost_credits"
+}
+
+func (l *BoostCreditModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+// BoostImpressionModel is a viewer who got the boosted user in the discovery
+// feed while the boost was active, every viewer counts once per boost.
+type BoostImpressionModel struct {
+	BoostId   string `json:"boost_id" gorm:"primaryKey"`
+	ViewerId  string `json:"viewer_id" gorm:"primaryKey"`
+	CreatedAt string `json:"created_at"`
+}
+
+func (c BoostImpressionModel) TableName() string {
+	return "boost_impressions"
+}
+
+func (l *BoostImpressionModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
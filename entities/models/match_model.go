@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MatchModel struct {
+	Id        string `json:"id"`
+	UserOneId string `json:"user_one_id"`
+	UserTwoId string `json:"user_two_id"`
+	// SwipeId is the right swipe that completed the pair, rewinding it
+	// reverts the match
+	SwipeId   *string    `json:"swipe_id,omitempty"`
+	CreatedAt string     `json:"created_at"`
+	DeletedAt *string    `json:"deleted_at,omitempty"`
+	UserOne   *UserModel `json:"user_one,omitempty" gorm:"foreignKey:UserOneId"`
+	UserTwo   *UserModel `json:"user_two,omitempty" gorm:"foreignKey:UserTwoId"`
+}
+
+func (c MatchModel) TableName() string {
+	return "matches"
+}
+
+func (l *MatchModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.Id = uuid.NewString()
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+// MatchPair returns both user ids in a stable order so a pair is always
+// stored and looked up the same way regardless of who swiped last.
+func MatchPair(userId, otherUserId string) (string, string) {
+	if userId < otherUserId {
+		return userId, otherUserId
+	}
+	return otherUserId, userId
+}
+
+// Partner returns the other user of the match from the point of view of userId.
+func (c MatchModel) Partner(userId string) *UserModel {
+	if c.UserOneId == userId {
+		return c.UserTwo
+	}
+	return c.UserOne
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	NotificationPhotoRejected = "photo_rejected"
+	NotificationSuperLike     = "super_like"
+)
+
+type NotificationModel struct {
+	Id          string  `json:"id"`
+	UserId      string  `json:"user_id"`
+	Type        string  `json:"type"`
+	Title       string  `json:"title"`
+	Message     string  `json:"message"`
+	ReferenceId *string `json:"reference_id"`
+	ReadAt      *string `json:"read_at"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+func (c NotificationModel) TableName() string {
+	return "notifications"
+}
+
+func (l *NotificationModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.Id = uuid.NewString()
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"dating-app-api/helpers"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PaymentPending     = "pending"
+	PaymentPaid        = "paid"
+	PaymentPartialPaid = "partial_paid"
+	PaymentOverPaid    = "over_paid"
+	PaymentExpired     = "expired"
+	PaymentRejected    = "rejected"
+)
+
+type PaymentModel struct {
+	Id        string  `json:"id"`
+	UserId    string  `json:"user_id"`
+	PlanId    *string `json:"plan_id"`
+	VaNumber  string  `json:"va_number"`
+	Amount    float64 `json:"amount"`
+	Status    string  `json:"status"`
+	ExpiredAt string  `json:"expired_at"`
+	PaidAt    *string `json:"paid_at"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt *string `json:"updated_at"`
+}
+
+func (c PaymentModel) TableName() string {
+	return "payments"
+}
+
+func (l *PaymentModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.Id = uuid.NewString()
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+func (l *PaymentModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+	l.UpdatedAt = &tNow
+	return
+}
+
+// IsExpired reports whether the virtual account can no longer be paid.
+func (l PaymentModel) IsExpired() bool {
+	expiredAt, err := helpers.ParseDateTime(l.ExpiredAt)
+	if err != nil {
+		return true
+	}
+	return time.Now().UTC().After(expiredAt)
+}
+
+// PaymentTransactionModel is one callback from the payment gateway, the
+// transaction id is unique so a retried callback is never applied twice.
+type PaymentTransactionModel struct {
+	Id            string  `json:"id"`
+	TransactionId string  `json:"transaction_id"`
+	PaymentId     *string `json:"payment_id"`
+	VaNumber      string  `json:"va_number"`
+	Amount        float64 `json:"amount"`
+	Response      *string `json:"response"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     *string `json:"updated_at"`
+}
+
+func (c PaymentTransactionModel) TableName() string {
+	return "payment_transactions"
+}
+
+func (l *PaymentTransactionModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.Id = uuid.NewString()
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+func (l *PaymentTransactionModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+	l.UpdatedAt = &tNow
+	return
+}
+
+type PaymentEventModel struct {
+	Id            string  `json:"id"`
+	PaymentId     *string `json:"payment_id"`
+	TransactionId string  `json:"transaction_id"`
+	FromStatus    *string `json:"from_status"`
+	ToStatus      string  `json:"to_status"`
+	Amount        float64 `json:"amount"`
+	Note          *string `json:"note"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+func (c PaymentEventModel) TableName() string {
+	return "payment_events"
+}
+
+func (l *PaymentEventModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.Id = uuid.NewString()
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const MaxPhotos = 6
+
+const (
+	PhotoProcessing = "processing"
+	PhotoReady      = "ready"
+	PhotoRejected   = "rejected"
+	// PhotoFailed is a photo the server could not process, the image itself
+	// may be fine and can be uploaded again
+	PhotoFailed = "failed"
+)
+
+// PhotoProcessingTimeout is how long a photo may stay processing, a photo
+// older than that was lost by a crashed or restarted worker.
+const PhotoProcessingTimeout = 10 * time.Minute
+
+const (
+	ModerationPending  = "pending"
+	ModerationApproved = "approved"
+	ModerationRejected = "rejected"
+
+	ModeratedByClassifier = "classifier"
+	ModeratedByAdmin      = "admin"
+)
+
+type PhotoModel struct {
+	Id               string  `json:"id"`
+	UserId           string  `json:"user_id"`
+	StorageKey       string  `json:"storage_key"`
+	Url              string  `json:"url"`
+	ThumbnailUrl     string  `json:"thumbnail_url"`
+	MediumUrl        string  `json:"medium_url"`
+	BlurHash         *string `json:"blurhash" gorm:"column:blurhash"`
+	Width            *int    `json:"width"`
+	Height           *int    `json:"height"`
+	Status           string  `json:"status"`
+	RejectedReason   *string `json:"rejected_reason"`
+	ModerationStatus string  `json:"moderation_status"`
+	ModerationReason *string `json:"moderation_reason"`
+	ModeratedBy      *string `json:"moderated_by"`
+	ModeratedAt      *string `json:"moderated_at"`
+	Position         int     `json:"position"`
+	IsPrimary        bool    `json:"is_primary"`
+	CreatedAt        string  `json:"created_at"`
+	UpdatedAt        *string `json:"updated_at"`
+}
+
+func (c PhotoModel) TableName() string {
+	return "photos"
+}
+
+func (l *PhotoModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.Id = uuid.NewString()
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+func (l *PhotoModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+	l.UpdatedAt = &tNow
+	return
+}
+
+// VariantKey is the storage key of one processed size of the photo, the
+// StorageKey is the common prefix of all of them.
+func (l *PhotoModel) VariantKey(variant string) string {
+	return l.StorageKey + "/" + variant + ".jpg"
+}
+
+// IsVisible reports whether other users may see the photo.
+func (l *PhotoModel) IsVisible() bool {
+	return l.Status == PhotoReady && l.ModerationStatus == ModerationApproved
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+const (
+	MinPreferenceAge = 18
+	MaxPreferenceAge = 99
+	// DefaultAgeSpread is how many years around the own age the default age
+	// range covers
+	DefaultAgeSpread = 5
+)
+
+type PreferenceModel struct {
+	UserId        string         `json:"user_id" gorm:"primaryKey"`
+	MinAge        int            `json:"min_age"`
+	MaxAge        int            `json:"max_age"`
+	Genders       pq.StringArray `json:"genders" gorm:"type:text[]"`
+	MaxDistance   *int           `json:"max_distance"`
+	MinHeight     *int           `json:"min_height"`
+	MaxHeight     *int           `json:"max_height"`
+	RequirePhotos bool           `json:"require_photos"`
+	CreatedAt     string         `json:"created_at"`
+	UpdatedAt     *string        `json:"updated_at"`
+}
+
+func (c PreferenceModel) TableName() string {
+	return "preferences"
+}
+
+func (l *PreferenceModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+// DefaultPreference derives the preferences of a user who never set them from
+// the profile, the same rule is used by the preferences migration backfill.
+func DefaultPreference(profile *ProfileModel) *PreferenceModel {
+	age := profile.Age()
+
+	genders := []string{profile.InterestedIn}
+	if profile.InterestedIn == InterestedInEveryone {
+		genders = []string{GenderMale, GenderFemale, GenderNonBinary}
+	}
+
+	return &PreferenceModel{
+		UserId:  profile.UserId,
+		MinAge:  clampAge(age - DefaultAgeSpread),
+		MaxAge:  clampAge(age + DefaultAgeSpread),
+		Genders: genders,
+	}
+}
+
+func clampAge(age int) int {
+	return max(MinPreferenceAge, min(MaxPreferenceAge, age))
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+const (
+	GenderMale      = "male"
+	GenderFemale    = "female"
+	GenderNonBinary = "non_binary"
+
+	InterestedInEveryone = "everyone"
+
+	MaxInterests = 10
+)
+
+type ProfileModel struct {
+	UserId       string         `json:"user_id" gorm:"primaryKey"`
+	DisplayName  string         `json:"display_name"`
+	Birthdate    string         `json:"birthdate"`
+	Gender       string         `json:"gender"`
+	InterestedIn string         `json:"interested_in"`
+	Bio          *string        `json:"bio"`
+	Job          *string        `json:"job"`
+	Education    *string        `json:"education"`
+	Height       *int           `json:"height"`
+	Location     *string        `json:"location"`
+	Interests    pq.StringArray `json:"interests" gorm:"type:text[]"`
+	CreatedAt    string         `json:"created_at"`
+	UpdatedAt    *string        `json:"updated_at"`
+}
+
+func (c ProfileModel) TableName() string {
+	return "profiles"
+}
+
+func (l *ProfileModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+// Age is counted in full years from the birthdate, a date column can be read
+// back either as a plain date or as a timestamp so only the date part is used.
+func (l ProfileModel) Age() int {
+	if len(l.Birthdate) < 10 {
+		return 0
+	}
+
+	birthdate, err := time.Parse("2006-01-02", l.Birthdate[:10])
+	if err != nil {
+		return 0
+	}
+
+	return AgeAt(birthdate, time.Now())
+}
+
+func AgeAt(birthdate time.Time, now time.Time) int {
+	age := now.Year() - birthdate.Year()
+	if now.Month() < birthdate.Month() || (now.Month() == birthdate.Month() && now.Day() < birthdate.Day()) {
+		age--
+	}
+	return age
+}
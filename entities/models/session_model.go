@@ -0,0 +1,13 @@
+package models
+
+// SessionModel is a signed in device, it lives in redis as long as its
+// refresh token does.
+type SessionModel struct {
+	Id         string `json:"id"`
+	UserId     string `json:"user_id"`
+	DeviceName string `json:"device_name"`
+	Ip         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at"`
+}
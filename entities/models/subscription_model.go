@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+const (
+	SubscriptionActive  = "active"
+	SubscriptionExpired = "expired"
+)
+
+const (
+	EntitlementUnlimitedSwipes = "unlimited_swipes"
+	EntitlementSeeLikes        = "see_likes"
+	EntitlementRewind          = "rewind"
+)
+
+type PlanModel struct {
+	Id           string         `json:"id"`
+	Code         string         `json:"code"`
+	Name         string         `json:"name"`
+	Price        float64        `json:"price"`
+	DurationDays int            `json:"duration_days"`
+	Entitlements pq.StringArray `json:"entitlements" gorm:"type:text[]"`
+	// BoostCredits are granted with every purchase of the plan
+	BoostCredits int     `json:"boost_credits"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    *string `json:"updated_at"`
+}
+
+func (c PlanModel) TableName() string {
+	return "plans"
+}
+
+type SubscriptionModel struct {
+	Id        string     `json:"id"`
+	UserId    string     `json:"user_id"`
+	PlanId    string     `json:"plan_id"`
+	PaymentId *string    `json:"payment_id"`
+	Status    string     `json:"status"`
+	StartAt   string     `json:"start_at"`
+	EndAt     string     `json:"end_at"`
+	CreatedAt string     `json:"created_at"`
+	UpdatedAt *string    `json:"updated_at"`
+	Plan      *PlanModel `json:"plan,omitempty" gorm:"foreignKey:PlanId"`
+}
+
+func (c SubscriptionModel) TableName() string {
+	return "subscriptions"
+}
+
+func (l *SubscriptionModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.Id = uuid.NewString()
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+func (l *SubscriptionModel) BeforeUpdate(tx *gorm.DB) (err error) {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+	l.UpdatedAt = &tNow
+	return
+}
+
+// SubscriptionStatus is the live premium state of a user, it is resolved on
+// every authenticated request instead of trusting the token claims.
+type SubscriptionStatus struct {
+	Active       bool     `json:"active"`
+	PlanCode     string   `json:"plan_code,omitempty"`
+	Entitlements []string `json:"entitlements"`
+	EndAt        string   `json:"end_at,omitempty"`
+}
+
+func (s SubscriptionStatus) HasEntitlement(entitlement string) bool {
+	if !s.Active {
+		return false
+	}
+
+	for _, e := range s.Entitlements {
+		if e == entitlement {
+			return true
+		}
+	}
+	return false
+}
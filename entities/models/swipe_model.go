@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	SwipeLeft  = "left"
+	SwipeRight = "right"
+	// SwipeSuper is only a request type, a super like is stored as a right
+	// swipe with IsSuper set
+	SwipeSuper = "super"
+)
+
+type SwipeModel struct {
+	Id        string     `json:"id"`
+	SwiperId  string     `json:"swiper_id"`
+	TargetId  string     `json:"target_id"`
+	Direction string     `json:"direction"`
+	IsSuper   bool       `json:"is_super"`
+	CreatedAt string     `json:"created_at"`
+	Swiper    *UserModel `json:"swiper,omitempty" gorm:"foreignKey:SwiperId"`
+	// PreviousDirection is the direction the swipe replaced, nil for the
+	// first swipe of the pair
+	PreviousDirection *string `json:"previous_direction,omitempty"`
+}
+
+func (c SwipeModel) TableName() string {
+	return "swipes"
+}
+
+func (l *SwipeModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.Id = uuid.NewString()
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
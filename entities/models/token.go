@@ -2,6 +2,7 @@ package models
 
 type TokenMetaData struct {
 	Id     string `json:"id"`
+	Sid    string `json:"sid"`
 	RtId   string `json:"rt_id"`
 	Exp    int64  `json:"exp"`
 	Verify bool   `json:"verify"`
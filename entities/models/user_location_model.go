@@ -0,0 +1,35 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// LocationGrid is the step in degrees the stored coordinates are snapped to,
+// about 1 km. The exact position is never stored, so distance filters can not
+// be used to trilaterate a user closer than the grid.
+const LocationGrid = 0.01
+
+type UserLocationModel struct {
+	UserId    string  `json:"user_id" gorm:"primaryKey"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt *string `json:"updated_at"`
+}
+
+func (c UserLocationModel) TableName() string {
+	return "user_locations"
+}
+
+func (l *UserLocationModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+// CoarsenCoordinate snaps a latitude or longitude to the location grid.
+func CoarsenCoordinate(value float64) float64 {
+	return math.Round(value/LocationGrid) * LocationGrid
+}
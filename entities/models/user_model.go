@@ -8,14 +8,24 @@ import (
 )
 
 type UserModel struct {
-	Id          string  `json:"id"`
-	Username    string  `json:"username"`
-	PhoneNumber string  `json:"phone_number"`
-	Password    string  `json:"password"`
-	Verified    bool    `json:"verified"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   *string `json:"updated_at"`
-	DeletedAt   *string `json:"deleted_at,omitempty"`
+	Id           string        `json:"id"`
+	Username     string        `json:"username"`
+	PhoneNumber  string        `json:"phone_number"`
+	Password     string        `json:"password"`
+	Verified     bool          `json:"verified"`
+	LastActiveAt string        `json:"last_active_at"`
+	CreatedAt    string        `json:"created_at"`
+	UpdatedAt    *string       `json:"updated_at"`
+	DeletedAt    *string       `json:"deleted_at,omitempty"`
+	Profile      *ProfileModel `json:"profile,omitempty" gorm:"foreignKey:UserId"`
+	Photos       []*PhotoModel `json:"photos,omitempty" gorm:"foreignKey:UserId"`
+	// Distance in km from the discovery origin, the desirability Rating,
+	// whether the user super liked the viewer and whether the user is boosted,
+	// only set by the discovery query
+	Distance   *float64 `json:"-" gorm:"->"`
+	Rating     *float64 `json:"-" gorm:"->"`
+	SuperLiked bool     `json:"-" gorm:"->"`
+	Boosted    bool     `json:"-" gorm:"->"`
 }
 
 func (c UserModel) TableName() string {
@@ -25,6 +35,7 @@ func (c UserModel) TableName() string {
 func (l *UserModel) BeforeCreate(tx *gorm.DB) (err error) {
 	l.Id = uuid.NewString()
 	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	l.LastActiveAt = l.CreatedAt
 	return
 }
 
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserRatingModel struct {
+	UserId      string  `json:"user_id" gorm:"primaryKey"`
+	Rating      float64 `json:"rating"`
+	SwipesCount int     `json:"swipes_count"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   *string `json:"updated_at"`
+}
+
+func (c UserRatingModel) TableName() string {
+	return "user_ratings"
+}
+
+func (l *UserRatingModel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return
+}
+
+// RatingBucket counts the users with a rating from Floor up to the next
+// bucket.
+type RatingBucket struct {
+	Floor float64 `json:"floor"`
+	Count int64   `json:"count"`
+}
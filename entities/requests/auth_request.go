@@ -3,8 +3,11 @@ package requests
 import "github.com/thedevsaddam/govalidator"
 
 type AuthRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	DeviceName string `json:"device_name"`
+	Ip         string
+	UserAgent  string
 }
 
 func (h *AuthRequest) ValiadateAuthLogin() interface{} {
@@ -12,8 +15,9 @@ func (h *AuthRequest) ValiadateAuthLogin() interface{} {
 	validator := govalidator.New(govalidator.Options{
 		Data: h,
 		Rules: govalidator.MapData{
-			"username": []string{"required", "max:50"},
-			"password": []string{"required"},
+			"username":    []string{"required", "max:50"},
+			"password":    []string{"required"},
+			"device_name": []string{"max:100"},
 		},
 		RequiredDefault: true,
 	}).ValidateStruct()
@@ -0,0 +1,28 @@
+package requests
+
+import "github.com/thedevsaddam/govalidator"
+
+// GrantBoostCreditRequest lets the admin hand out boost credits, e.g. for
+// promotions.
+type GrantBoostCreditRequest struct {
+	UserId  string `json:"user_id"`
+	Credits int    `json:"credits"`
+}
+
+func (h *GrantBoostCreditRequest) ValiadateGrantBoostCredit() interface{} {
+
+	validator := govalidator.New(govalidator.Options{
+		Data: h,
+		Rules: govalidator.MapData{
+			"user_id": []string{"required", "uuid"},
+			"credits": []string{"required", "numeric_between:1,100"},
+		},
+		RequiredDefault: true,
+	}).ValidateStruct()
+
+	if len(validator) > 0 {
+		return validator
+	}
+
+	return nil
+}
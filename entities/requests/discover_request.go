@@ -0,0 +1,65 @@
+package requests
+
+import "github.com/thedevsaddam/govalidator"
+
+type DiscoverRequest struct {
+	MaxDistance int `json:"max_distance" query:"max_distance"`
+}
+
+func (h *DiscoverRequest) ValiadateDiscover() interface{} {
+
+	validator := govalidator.New(govalidator.Options{
+		Data: h,
+		Rules: govalidator.MapData{
+			"max_distance": []string{"numeric_between:0,500"},
+		},
+		RequiredDefault: false,
+	}).ValidateStruct()
+
+	if len(validator) > 0 {
+		return validator
+	}
+
+	return nil
+}
+
+// DiscoverFilter is the typed query of the discovery feed.
+type DiscoverFilter struct {
+	// ExcludeIds are never returned, e.g. the caller and users already swiped
+	ExcludeIds []string
+	// OnlyWithProfile leaves out users who did not fill their dating profile
+	OnlyWithProfile bool
+	// Origin is the caller location, candidates get a distance when it is set
+	Origin *Coordinate
+	// MaxDistanceKm limits the candidates to the distance from Origin, 0 means
+	// no limit
+	MaxDistanceKm int
+	// Preference is what the viewer looks for in the candidates
+	Preference *DiscoverPreference
+	// Viewer is checked against the preferences of the candidates, so nobody
+	// is shown to a user they would never be shown
+	Viewer *DiscoverViewer
+	// BoostedIds are the users with a running boost, they come first
+	BoostedIds []string
+}
+
+type DiscoverPreference struct {
+	MinAge        int
+	MaxAge        int
+	Genders       []string
+	MinHeight     *int
+	MaxHeight     *int
+	RequirePhotos bool
+}
+
+type DiscoverViewer struct {
+	Id     string
+	Age    int
+	Gender string
+	Height *int
+}
+
+type Coordinate struct {
+	Latitude  float64
+	Longitude float64
+}
@@ -0,0 +1,33 @@
+package requests
+
+import "net/url"
+
+type LocationRequest struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// ValiadateLocation checks the coordinates by hand, govalidator can not read
+// float pointers and 0 is a valid coordinate so the fields can not be plain
+// floats either.
+func (h *LocationRequest) ValiadateLocation() interface{} {
+	validator := url.Values{}
+
+	if h.Latitude == nil {
+		validator.Add("latitude", "The latitude field is required")
+	} else if *h.Latitude < -90 || *h.Latitude > 90 {
+		validator.Add("latitude", "The latitude field must be between -90 and 90")
+	}
+
+	if h.Longitude == nil {
+		validator.Add("longitude", "The longitude field is required")
+	} else if *h.Longitude < -180 || *h.Longitude > 180 {
+		validator.Add("longitude", "The longitude field must be between -180 and 180")
+	}
+
+	if len(validator) > 0 {
+		return validator
+	}
+
+	return nil
+}
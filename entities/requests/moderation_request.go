@@ -0,0 +1,24 @@
+package requests
+
+import "github.com/thedevsaddam/govalidator"
+
+type RejectPhotoRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h *RejectPhotoRequest) ValiadateRejectPhoto() interface{} {
+
+	validator := govalidator.New(govalidator.Options{
+		Data: h,
+		Rules: govalidator.MapData{
+			"reason": []string{"required", "max:255"},
+		},
+		RequiredDefault: true,
+	}).ValidateStruct()
+
+	if len(validator) > 0 {
+		return validator
+	}
+
+	return nil
+}
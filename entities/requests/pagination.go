@@ -1,6 +1,9 @@
 package requests
 
-import "strings"
+import (
+	"math"
+	"strings"
+)
 
 type MetaPaginationRequest struct {
 	Page      int    `json:"page" query:"page"`
@@ -42,3 +45,9 @@ func (p *MetaPaginationRequest) ParsePagination() MetaPaginationRequest {
 	p.Offset = offset
 	return *p
 }
+
+func (p *MetaPaginationRequest) ParseTotalPage(count int64) MetaPaginationRequest {
+	p.Count = count
+	p.TotalPage = int(math.Ceil(float64(count) / float64(p.Limit)))
+	return *p
+}
@@ -0,0 +1,24 @@
+package requests
+
+import "github.com/thedevsaddam/govalidator"
+
+type CreatePaymentRequest struct {
+	PlanCode string `json:"plan_code"`
+}
+
+func (h *CreatePaymentRequest) ValiadateCreatePayment() interface{} {
+
+	validator := govalidator.New(govalidator.Options{
+		Data: h,
+		Rules: govalidator.MapData{
+			"plan_code": []string{"required", "char_libs", "max:20"},
+		},
+		RequiredDefault: true,
+	}).ValidateStruct()
+
+	if len(validator) > 0 {
+		return validator
+	}
+
+	return nil
+}
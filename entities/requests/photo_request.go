@@ -0,0 +1,24 @@
+package requests
+
+import "github.com/thedevsaddam/govalidator"
+
+type ReorderPhotoRequest struct {
+	PhotoIds []string `json:"photo_ids"`
+}
+
+func (h *ReorderPhotoRequest) ValiadateReorderPhoto() interface{} {
+
+	validator := govalidator.New(govalidator.Options{
+		Data: h,
+		Rules: govalidator.MapData{
+			"photo_ids": []string{"required", "max:6"},
+		},
+		RequiredDefault: true,
+	}).ValidateStruct()
+
+	if len(validator) > 0 {
+		return validator
+	}
+
+	return nil
+}
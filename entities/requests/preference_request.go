@@ -0,0 +1,59 @@
+package requests
+
+import (
+	"dating-app-api/entities/models"
+	"net/url"
+	"slices"
+
+	"github.com/thedevsaddam/govalidator"
+)
+
+// PreferenceRequest uses 0 for the optional numbers, govalidator skips empty
+// values but can not read pointers to numbers.
+type PreferenceRequest struct {
+	MinAge        int      `json:"min_age"`
+	MaxAge        int      `json:"max_age"`
+	Genders       []string `json:"genders"`
+	MaxDistance   int      `json:"max_distance"`
+	MinHeight     int      `json:"min_height"`
+	MaxHeight     int      `json:"max_height"`
+	RequirePhotos bool     `json:"require_photos"`
+}
+
+func (h *PreferenceRequest) ValiadatePreference() interface{} {
+
+	validator := govalidator.New(govalidator.Options{
+		Data: h,
+		Rules: govalidator.MapData{
+			"min_age":      []string{"required", "numeric_between:18,99"},
+			"max_age":      []string{"required", "numeric_between:18,99"},
+			"genders":      []string{"required"},
+			"max_distance": []string{"numeric_between:1,500"},
+			"min_height":   []string{"numeric_between:100,250"},
+			"max_height":   []string{"numeric_between:100,250"},
+		},
+		RequiredDefault: false,
+	}).ValidateStruct()
+
+	errs := url.Values(validator)
+	for _, gender := range h.Genders {
+		if !slices.Contains([]string{models.GenderMale, models.GenderFemale, models.GenderNonBinary}, gender) {
+			errs.Add("genders", "The genders field must only contain male, female or non_binary")
+			break
+		}
+	}
+
+	if h.MaxAge < h.MinAge {
+		errs.Add("max_age", "The max_age field must not be less than min_age")
+	}
+
+	if h.MaxHeight > 0 && h.MaxHeight < h.MinHeight {
+		errs.Add("max_height", "The max_height field must not be less than min_height")
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package requests
+
+import (
+	"dating-app-api/entities/models"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/thedevsaddam/govalidator"
+)
+
+type ProfileRequest struct {
+	DisplayName  string   `json:"display_name"`
+	Birthdate    string   `json:"birthdate"`
+	Gender       string   `json:"gender"`
+	InterestedIn string   `json:"interested_in"`
+	Bio          *string  `json:"bio"`
+	Job          *string  `json:"job"`
+	Education    *string  `json:"education"`
+	Height       *int     `json:"height"`
+	Location     *string  `json:"location"`
+	Interests    []string `json:"interests"`
+}
+
+func (h *ProfileRequest) ValiadateProfile() interface{} {
+
+	validator := govalidator.New(govalidator.Options{
+		Data: h,
+		Rules: govalidator.MapData{
+			"display_name":  []string{"required", "min:2", "max:50"},
+			"birthdate":     []string{"required", "date", "adult_libs"},
+			"gender":        []string{"required", "in:male,female,non_binary"},
+			"interested_in": []string{"required", "in:male,female,non_binary,everyone"},
+			"bio":           []string{"max:500"},
+			"job":           []string{"max:100"},
+			"education":     []string{"max:100"},
+			"height":        []string{"numeric_between:100,250"},
+			"location":      []string{"max:100"},
+		},
+		RequiredDefault: false,
+	}).ValidateStruct()
+
+	errs := url.Values(validator)
+	if len(h.Interests) > models.MaxInterests {
+		errs.Add("interests", fmt.Sprintf("The interests field may not have more than %d items", models.MaxInterests))
+	}
+
+	for _, interest := range h.Interests {
+		if length := len(strings.TrimSpace(interest)); length < 2 || length > 30 {
+			errs.Add("interests", "The interests field items must be between 2 and 30 characters")
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
@@ -13,7 +13,7 @@ func (h *SwipeRequest) ValiadateSwipe() interface{} {
 		Data: h,
 		Rules: govalidator.MapData{
 			"user_id": []string{"required", "uuid"},
-			"type":    []string{"required", "in:left,right"},
+			"type":    []string{"required", "in:left,right,super"},
 		},
 		RequiredDefault: true,
 	}).ValidateStruct()
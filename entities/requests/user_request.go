@@ -6,6 +6,9 @@ type CreateUserRequest struct {
 	Username    string `json:"username"`
 	Password    string `json:"password"`
 	PhoneNumber string `json:"phone_number"`
+	DeviceName  string `json:"device_name"`
+	Ip          string
+	UserAgent   string
 }
 
 func (h *CreateUserRequest) ValiadateCreateUser() interface{} {
@@ -16,6 +19,7 @@ func (h *CreateUserRequest) ValiadateCreateUser() interface{} {
 			"username":     []string{"required", "char_libs", "min:3", "max:50"},
 			"password":     []string{"required"},
 			"phone_number": []string{"numeric_null_libs", "max:15"},
+			"device_name":  []string{"max:100"},
 		},
 		RequiredDefault: true,
 	}).ValidateStruct()
@@ -46,8 +50,6 @@ func (h *CreateUserRequest) ValiadateCheckUsername() interface{} {
 
 type UpdateUserRequest struct {
 	Username    string `json:"username"`
-	NewPassword string `json:"new_password"`
-	OldPassword string `json:"old_password"`
 	PhoneNumber string `json:"phone_number"`
 }
 
@@ -56,9 +58,7 @@ func (h *UpdateUserRequest) ValiadateUpdateUser() interface{} {
 	validator := govalidator.New(govalidator.Options{
 		Data: h,
 		Rules: govalidator.MapData{
-			"username":     []string{"required", "char_libs", "max:50"},
-			"email":        []string{"required", "email", "max:50"},
-			"password":     []string{"required"},
+			"username":     []string{"required", "char_libs", "min:3", "max:50"},
 			"phone_number": []string{"numeric_null_libs", "max:15"},
 		},
 		RequiredDefault: true,
@@ -72,8 +72,12 @@ func (h *UpdateUserRequest) ValiadateUpdateUser() interface{} {
 }
 
 type VerifyUser struct {
-	VANumber string  `json:"va_number"`
-	Amount   float64 `json:"amount"`
+	TransactionId string  `json:"transaction_id"`
+	VANumber      string  `json:"va_number"`
+	Amount        float64 `json:"amount"`
+	DeviceName    string  `json:"device_name"`
+	Ip            string
+	UserAgent     string
 }
 
 func (h *VerifyUser) VerifyUser() interface{} {
@@ -81,8 +85,10 @@ func (h *VerifyUser) VerifyUser() interface{} {
 	validator := govalidator.New(govalidator.Options{
 		Data: h,
 		Rules: govalidator.MapData{
-			"va_number": []string{"required", "number", "max:16"},
-			"amount":    []string{"required", "numeric"},
+			"transaction_id": []string{"required", "char_libs", "max:64"},
+			"va_number":      []string{"required", "number", "max:16"},
+			"amount":         []string{"required", "numeric"},
+			"device_name":    []string{"max:100"},
 		},
 		RequiredDefault: true,
 	}).ValidateStruct()
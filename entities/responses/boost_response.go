@@ -0,0 +1,30 @@
+package responses
+
+import "dating-app-api/entities/models"
+
+type BoostResponse struct {
+	Id         string  `json:"id"`
+	Multiplier float64 `json:"multiplier"`
+	StartAt    string  `json:"start_at"`
+	EndAt      string  `json:"end_at"`
+	Active     bool    `json:"active"`
+	// Impressions and Likes are gathered while the boost is active
+	Impressions int64 `json:"impressions"`
+	Likes       int64 `json:"likes"`
+	// CreditsLeft is only set when the boost is started
+	CreditsLeft *int `json:"credits_left,omitempty"`
+}
+
+func NewBoostResponse(boost *models.BoostModel, active bool) *BoostResponse {
+	return &BoostResponse{
+		Id:         boost.Id,
+		Multiplier: boost.Multiplier,
+		StartAt:    boost.StartAt,
+		EndAt:      boost.EndAt,
+		Active:     active,
+	}
+}
+
+type BoostCreditResponse struct {
+	Balance int `json:"balance"`
+}
@@ -11,6 +11,8 @@ var (
 	statusBadRequest    = http.StatusBadRequest
 	statusNotFound      = http.StatusNotFound
 	statusUnAuthorize   = http.StatusUnauthorized
+	statusForbidden     = http.StatusForbidden
+	statusTooManyReq    = http.StatusTooManyRequests
 	internalServerError = http.StatusInternalServerError
 )
 
@@ -20,6 +22,7 @@ type Response struct {
 	Meta       *requests.MetaPaginationRequest `json:"meta,omitempty"`
 	Data       interface{}                     `json:"data,omitempty"`
 	Validation interface{}                     `json:"validation,omitempty"`
+	ErrorCode  string                          `json:"error_code,omitempty"`
 }
 
 type CommondResponse struct{}
@@ -72,6 +75,25 @@ func (cmd CommondResponse) StatusUnAuthorize(err string) Response {
 	return jsonResp
 }
 
+func (cmd CommondResponse) StatusForbidden(data interface{}, errorCode string, message string) Response {
+	jsonResp := Response{
+		StatusCode: statusForbidden,
+		Message:    message,
+		Data:       data,
+		ErrorCode:  errorCode,
+	}
+	return jsonResp
+}
+
+func (cmd CommondResponse) StatusTooManyRequest(data interface{}, message string) Response {
+	jsonResp := Response{
+		StatusCode: statusTooManyReq,
+		Message:    message,
+		Data:       data,
+	}
+	return jsonResp
+}
+
 func (cmd CommondResponse) StatusServerError(err string) Response {
 	jsonResp := Response{
 		StatusCode: internalServerError,
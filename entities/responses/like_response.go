@@ -0,0 +1,38 @@
+package responses
+
+import "dating-app-api/entities/models"
+
+// LikeResponse is a user who swiped right on the caller. The user is only
+// revealed with the see likes entitlement, everyone else gets the blurhash of
+// the primary photo as placeholder.
+type LikeResponse struct {
+	Id       string              `json:"id"`
+	Revealed bool                `json:"revealed"`
+	Super    bool                `json:"super"`
+	User     *UserPublicResponse `json:"user"`
+	BlurHash *string             `json:"blurhash"`
+	LikedAt  string              `json:"liked_at"`
+}
+
+func NewLikeResponse(swipe *models.SwipeModel, revealed bool) *LikeResponse {
+	like := &LikeResponse{
+		Id:       swipe.Id,
+		Revealed: revealed,
+		Super:    swipe.IsSuper,
+		LikedAt:  swipe.CreatedAt,
+	}
+
+	if swipe.Swiper == nil {
+		return like
+	}
+
+	if photos := visiblePhotos(swipe.Swiper); len(photos) > 0 {
+		like.BlurHash = photos[0].BlurHash
+	}
+
+	if revealed {
+		like.User = NewUserPublicResponse(swipe.Swiper)
+	}
+
+	return like
+}
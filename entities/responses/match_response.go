@@ -0,0 +1,7 @@
+package responses
+
+type MatchResponse struct {
+	Id        string              `json:"id"`
+	User      *UserPublicResponse `json:"user"`
+	CreatedAt string              `json:"created_at"`
+}
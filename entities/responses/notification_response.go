@@ -0,0 +1,25 @@
+package responses
+
+import "dating-app-api/entities/models"
+
+type NotificationResponse struct {
+	Id          string  `json:"id"`
+	Type        string  `json:"type"`
+	Title       string  `json:"title"`
+	Message     string  `json:"message"`
+	ReferenceId *string `json:"reference_id"`
+	ReadAt      *string `json:"read_at"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+func NewNotificationResponse(notification *models.NotificationModel) *NotificationResponse {
+	return &NotificationResponse{
+		Id:          notification.Id,
+		Type:        notification.Type,
+		Title:       notification.Title,
+		Message:     notification.Message,
+		ReferenceId: notification.ReferenceId,
+		ReadAt:      notification.ReadAt,
+		CreatedAt:   notification.CreatedAt,
+	}
+}
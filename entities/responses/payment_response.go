@@ -0,0 +1,12 @@
+package responses
+
+type PaymentResponse struct {
+	Id        string  `json:"id"`
+	PlanId    *string `json:"plan_id"`
+	VaNumber  string  `json:"va_number"`
+	Amount    float64 `json:"amount"`
+	Status    string  `json:"status"`
+	ExpiredAt string  `json:"expired_at"`
+	PaidAt    *string `json:"paid_at"`
+	CreatedAt string  `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package responses
+
+import "dating-app-api/entities/models"
+
+type PhotoResponse struct {
+	Id               string  `json:"id"`
+	Status           string  `json:"status"`
+	RejectedReason   *string `json:"rejected_reason,omitempty"`
+	ModerationStatus string  `json:"moderation_status"`
+	ModerationReason *string `json:"moderation_reason,omitempty"`
+	Url              string  `json:"url,omitempty"`
+	ThumbnailUrl     string  `json:"thumbnail_url,omitempty"`
+	MediumUrl        string  `json:"medium_url,omitempty"`
+	BlurHash         *string `json:"blurhash,omitempty"`
+	Width            *int    `json:"width,omitempty"`
+	Height           *int    `json:"height,omitempty"`
+	Position         int     `json:"position"`
+	IsPrimary        bool    `json:"is_primary"`
+	CreatedAt        string  `json:"created_at"`
+}
+
+func NewPhotoResponse(photo *models.PhotoModel) *PhotoResponse {
+	return &PhotoResponse{
+		Id:               photo.Id,
+		Status:           photo.Status,
+		RejectedReason:   photo.RejectedReason,
+		ModerationStatus: photo.ModerationStatus,
+		ModerationReason: photo.ModerationReason,
+		Url:              photo.Url,
+		ThumbnailUrl:     photo.ThumbnailUrl,
+		MediumUrl:        photo.MediumUrl,
+		BlurHash:         photo.BlurHash,
+		Width:            photo.Width,
+		Height:           photo.Height,
+		Position:         photo.Position,
+		IsPrimary:        photo.IsPrimary,
+		CreatedAt:        photo.CreatedAt,
+	}
+}
+
+type ModerationPhotoResponse struct {
+	PhotoResponse
+	UserId string `json:"user_id"`
+}
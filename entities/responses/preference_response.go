@@ -0,0 +1,27 @@
+package responses
+
+import "dating-app-api/entities/models"
+
+type PreferenceResponse struct {
+	MinAge        int      `json:"min_age"`
+	MaxAge        int      `json:"max_age"`
+	Genders       []string `json:"genders"`
+	MaxDistance   *int     `json:"max_distance"`
+	MinHeight     *int     `json:"min_height"`
+	MaxHeight     *int     `json:"max_height"`
+	RequirePhotos bool     `json:"require_photos"`
+	UpdatedAt     *string  `json:"updated_at"`
+}
+
+func NewPreferenceResponse(preference *models.PreferenceModel) *PreferenceResponse {
+	return &PreferenceResponse{
+		MinAge:        preference.MinAge,
+		MaxAge:        preference.MaxAge,
+		Genders:       preference.Genders,
+		MaxDistance:   preference.MaxDistance,
+		MinHeight:     preference.MinHeight,
+		MaxHeight:     preference.MaxHeight,
+		RequirePhotos: preference.RequirePhotos,
+		UpdatedAt:     preference.UpdatedAt,
+	}
+}
@@ -0,0 +1,26 @@
+package responses
+
+import "dating-app-api/entities/models"
+
+type SessionResponse struct {
+	Id         string `json:"id"`
+	DeviceName string `json:"device_name"`
+	Ip         string `json:"ip"`
+	UserAgent  string `json:"user_agent"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at"`
+	// Current marks the session of the token used for the request
+	Current bool `json:"current"`
+}
+
+func NewSessionResponse(session *models.SessionModel, current bool) SessionResponse {
+	return SessionResponse{
+		Id:         session.Id,
+		DeviceName: session.DeviceName,
+		Ip:         session.Ip,
+		UserAgent:  session.UserAgent,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		Current:    current,
+	}
+}
@@ -0,0 +1,17 @@
+package responses
+
+type PlanResponse struct {
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Price        float64  `json:"price"`
+	DurationDays int      `json:"duration_days"`
+	Entitlements []string `json:"entitlements"`
+	BoostCredits int      `json:"boost_credits"`
+}
+
+type SubscriptionResponse struct {
+	Active  bool          `json:"active"`
+	Plan    *PlanResponse `json:"plan,omitempty"`
+	StartAt string        `json:"start_at,omitempty"`
+	EndAt   string        `json:"end_at,omitempty"`
+}
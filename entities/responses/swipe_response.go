@@ -0,0 +1,33 @@
+package responses
+
+type SwipeResponse struct {
+	Id        string              `json:"id"`
+	UserId    string              `json:"user_id"`
+	Type      string              `json:"type"`
+	CreatedAt string              `json:"created_at"`
+	Matched   bool                `json:"matched"`
+	MatchId   string              `json:"match_id,omitempty"`
+	User      *UserPublicResponse `json:"user,omitempty"`
+	Quota     *QuotaResponse      `json:"quota,omitempty"`
+}
+
+// RewindResponse is the undone swipe, the user is returned so the card can be
+// shown again.
+type RewindResponse struct {
+	Id        string              `json:"id"`
+	UserId    string              `json:"user_id"`
+	Type      string              `json:"type"`
+	Unmatched bool                `json:"unmatched"`
+	User      *UserPublicResponse `json:"user,omitempty"`
+	Quota     *QuotaResponse      `json:"quota,omitempty"`
+}
+
+type QuotaResponse struct {
+	Unlimited bool   `json:"unlimited"`
+	Limit     *int   `json:"limit"`
+	Used      int    `json:"used"`
+	Remaining *int   `json:"remaining"`
+	ResetsAt  string `json:"resets_at"`
+	// SuperLikes is the super like balance, only set on the swipe quota
+	SuperLikes *QuotaResponse `json:"super_likes,omitempty"`
+}
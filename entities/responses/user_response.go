@@ -1,5 +1,11 @@
 package responses
 
+import (
+	"dating-app-api/entities/models"
+	"math"
+	"sort"
+)
+
 type UserResponse struct {
 	Id          string `json:"id,omitempty"`
 	Username    string `json:"username"`
@@ -9,5 +15,127 @@ type UserResponse struct {
 }
 
 type UserPublicResponse struct {
-	Username string `json:"username"`
+	Id          string   `json:"id"`
+	Username    string   `json:"username"`
+	DisplayName string   `json:"display_name,omitempty"`
+	Age         int      `json:"age,omitempty"`
+	Gender      string   `json:"gender,omitempty"`
+	Bio         *string  `json:"bio,omitempty"`
+	Job         *string  `json:"job,omitempty"`
+	Education   *string  `json:"education,omitempty"`
+	Height      *int     `json:"height,omitempty"`
+	Location    *string  `json:"location,omitempty"`
+	Interests   []string `json:"interests"`
+	Photos      []string `json:"photos"`
+	DistanceKm  *int     `json:"distance_km,omitempty"`
+	SuperLiked  bool     `json:"super_liked,omitempty"`
+}
+
+// NewUserPublicResponse only exposes what other users may see, the profile
+// fields are filled when the profile relation is loaded.
+func NewUserPublicResponse(user *models.UserModel) *UserPublicResponse {
+	if user == nil {
+		return nil
+	}
+
+	public := &UserPublicResponse{
+		Id:        user.Id,
+		Username:  user.Username,
+		Interests: []string{},
+		Photos:    []string{},
+	}
+
+	if user.Profile != nil {
+		public.DisplayName = user.Profile.DisplayName
+		public.Age = user.Profile.Age()
+		public.Gender = user.Profile.Gender
+		public.Bio = user.Profile.Bio
+		public.Job = user.Profile.Job
+		public.Education = user.Profile.Education
+		public.Height = user.Profile.Height
+		public.Location = user.Profile.Location
+		if len(user.Profile.Interests) > 0 {
+			public.Interests = user.Profile.Interests
+		}
+	}
+
+	for _, photo := range visiblePhotos(user) {
+		public.Photos = append(public.Photos, photo.Url)
+	}
+
+	if user.Distance != nil {
+		public.DistanceKm = roundDistance(*user.Distance)
+	}
+	public.SuperLiked = user.SuperLiked
+
+	return public
+}
+
+// visiblePhotos puts the primary photo first, the rest keep the order chosen
+// by the user, photos still processing or not approved by moderation are left
+// out.
+func visiblePhotos(user *models.UserModel) []*models.PhotoModel {
+	photos := []*models.PhotoModel{}
+	for _, photo := range user.Photos {
+		if photo.IsVisible() {
+			photos = append(photos, photo)
+		}
+	}
+
+	sort.SliceStable(photos, func(i, j int) bool {
+		if photos[i].IsPrimary != photos[j].IsPrimary {
+			return photos[i].IsPrimary
+		}
+		return photos[i].Position < photos[j].Position
+	})
+
+	return photos
+}
+
+// roundDistance never exposes the exact distance, it is rounded up to whole
+// kilometers (5 km steps past 10 km) so the position of a user can not be
+// pinned down by moving around.
+func roundDistance(km float64) *int {
+	step := 1.0
+	if km > 10 {
+		step = 5
+	}
+
+	rounded := int(math.Ceil(km/step) * step)
+	if rounded < 1 {
+		rounded = 1
+	}
+	return &rounded
+}
+
+type ProfileResponse struct {
+	DisplayName  string   `json:"display_name"`
+	Birthdate    string   `json:"birthdate"`
+	Age          int      `json:"age"`
+	Gender       string   `json:"gender"`
+	InterestedIn string   `json:"interested_in"`
+	Bio          *string  `json:"bio"`
+	Job          *string  `json:"job"`
+	Education    *string  `json:"education"`
+	Height       *int     `json:"height"`
+	Location     *string  `json:"location"`
+	Interests    []string `json:"interests"`
+	UpdatedAt    *string  `json:"updated_at"`
+}
+
+func NewProfileResponse(profile *models.ProfileModel) *ProfileResponse {
+	return &ProfileResponse{
+		DisplayName:  profile.DisplayName,
+		Birthdate:    profile.Birthdate[:10],
+		Age:          profile.Age(),
+		Gender:       profile.Gender,
+		InterestedIn: profile.InterestedIn,
+		Bio:          profile.Bio,
+		Job:          profile.Job,
+		Education:    profile.Education,
+		Height:       profile.Height,
+		Location:     profile.Location,
+		Interests:    append([]string{}, profile.Interests...),
+		UpdatedAt:    profile.UpdatedAt,
+	}
 }
@@ -0,0 +1,15 @@
+package helpers
+
+import "time"
+
+// Clock is the source of the current time for code that has to be checked
+// against a fixed or advancing time.
+type Clock interface {
+	Now() time.Time
+}
+
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,20 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// GenerateNumericCode returns a random string of digits with the given length
+func GenerateNumericCode(length int) (string, error) {
+	digits := make([]byte, length)
+	for i := range digits {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + n.Int64())
+	}
+
+	return string(digits), nil
+}
@@ -2,7 +2,7 @@ package helpers
 
 import "time"
 
-func GetTimeToMidnight() int {
+func GetTimeToMidnight() time.Duration {
 	// Get the current time
 	now := time.Now()
 
@@ -12,5 +12,16 @@ func GetTimeToMidnight() int {
 	// Calculate the duration until next midnight
 	durationUntilMidnight := nextMidnight.Sub(now)
 
-	return int(durationUntilMidnight.Hours())
+	return durationUntilMidnight
+}
+
+// ParseDateTime parses timestamps of the models, they are written as
+// "2006-01-02 15:04:05" but postgres returns them as RFC3339 when read back
+func ParseDateTime(value string) (time.Time, error) {
+	parsed, err := time.Parse("2006-01-02 15:04:05", value)
+	if err == nil {
+		return parsed, nil
+	}
+
+	return time.Parse(time.RFC3339Nano, value)
 }
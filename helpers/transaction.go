@@ -0,0 +1,31 @@
+package helpers
+
+import "gorm.io/gorm"
+
+const afterCommitKey = "helpers:after_commit"
+
+// AfterCommit queues fn until tx is committed with CommitTx. Side effects
+// outside of the database (redis, files, queues) are registered this way so a
+// rolled back transaction leaves nothing behind.
+func AfterCommit(tx *gorm.DB, fn func()) {
+	hooks, _ := tx.Statement.Settings.LoadOrStore(afterCommitKey, &[]func(){})
+	queue := hooks.(*[]func())
+	*queue = append(*queue, fn)
+}
+
+// CommitTx commits tx and runs the functions queued with AfterCommit in order
+// once the commit succeeded.
+func CommitTx(tx *gorm.DB) *gorm.DB {
+	comm := tx.Commit()
+	if comm.Error != nil {
+		return comm
+	}
+
+	if hooks, ok := tx.Statement.Settings.Load(afterCommitKey); ok {
+		for _, fn := range *hooks.(*[]func()) {
+			fn()
+		}
+	}
+
+	return comm
+}
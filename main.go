@@ -1,13 +1,24 @@
 package main
 
 import (
+	"context"
 	"dating-app-api/configs"
 	"dating-app-api/deliveries/routes"
 	"dating-app-api/deliveries/validators"
 	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/services"
+	"dating-app-api/utils"
+	"dating-app-api/utils/imageproc"
+	"dating-app-api/utils/ranking"
+	"dating-app-api/utils/rating"
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -40,6 +51,12 @@ func main() {
 
 	validators.AddValidatorLibs()
 
+	// the workers are started by the api only, tools reading the env do not
+	// need them
+	env.PhotoPool = imageproc.NewPool(env.PhotoWorkers, env.PhotoQueueSize)
+	env.Ranker = ranking.NewDefaultRanker(env.RankWeights, env.RankDebug)
+	env.RatingUpdater = rating.NewUpdater(repositories.NewRatingRepository(db), rating.NewElo(env.RatingK), env.RatingQueueSize)
+
 	fiberApp := fiber.New(fiber.Config{
 		JSONEncoder: json.Marshal,
 		JSONDecoder: json.Unmarshal,
@@ -69,6 +86,13 @@ func main() {
 		},
 	}))
 
+	// photos of the local storage driver are served by the app itself
+	if env.StorageDriver == utils.StorageLocal {
+		routes.BuildUploadRoute(fiberApp, env, db)
+	}
+
+	routes.BuildWellKnownRoute(fiberApp, env)
+
 	route := fiberApp.Group(fmt.Sprintf("/api/%s/", env.AppVersion))
 	route.Use(logger.New(logger.Config{
 		Format: `{"host":"${host}","pid":"${pid}","time":"${time}","request-id":"${locals:requestid}","status":"${status}","method":"${method}","latency":"${latency}","path":"${path}",` +
@@ -80,8 +104,42 @@ func main() {
 	// init app
 	routes.Build(route, env, db)
 
+	// the periodic jobs run until the server stops
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	common := responses.NewResponseAPI()
+	photoService := services.NewPhotoService(repositories.NewPhotoRepository(db), repositories.NewNotificationRepository(db), *common, env.Redis, &env, db)
+	subscriptionService := services.NewSubscriptionService(repositories.NewSubscriptionRepository(db), *common, env.Redis, &env, db)
+
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		photoService.RunStalePhotoWorker(workerCtx, time.Minute)
+	}()
+	go func() {
+		defer workers.Done()
+		subscriptionService.RunExpiryWorker(workerCtx, time.Minute)
+	}()
+
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		<-quit
+
+		if err := fiberApp.Shutdown(); err != nil {
+			log.Println("error shutdown server :", err)
+		}
+	}()
+
 	err = fiberApp.Listen(fmt.Sprintf("%v:%v", env.AppHost, env.AppPort))
 	if err != nil {
 		log.Fatalf("Failed to start server: %v", err.Error())
 	}
+
+	// the periodic jobs are stopped first, then the queued photos and rating
+	// updates are finished before exiting
+	stopWorkers()
+	workers.Wait()
+	env.PhotoPool.Close()
+	env.RatingUpdater.Close()
 }
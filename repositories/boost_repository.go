@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"dating-app-api/entities/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BoostRepositoryInterface interface {
+	GetDetailCredit(userId string) (*models.BoostCreditModel, error)
+	AddCredits(userId string, credits int, tx *gorm.DB) error
+	UseCredit(userId string, tx *gorm.DB) (*models.BoostCreditModel, error)
+	CreateBoost(model *models.BoostModel, tx *gorm.DB) (*models.BoostModel, error)
+	GetDetailBoost(whereClause interface{}) (*models.BoostModel, error)
+	GetListBoost(ids []string) ([]*models.BoostModel, error)
+	GetActiveBoost(userId string, tx *gorm.DB) (*models.BoostModel, error)
+	CreateImpressions(impressions []*models.BoostImpressionModel) error
+	CountImpressions(boostId string) (int64, error)
+	CountLikes(boost *models.BoostModel) (int64, error)
+}
+
+type boostRepository struct {
+	db *gorm.DB
+}
+
+func NewBoostRepository(db *gorm.DB) BoostRepositoryInterface {
+	return &boostRepository{
+		db: db,
+	}
+}
+
+func (repo *boostRepository) GetDetailCredit(userId string) (*models.BoostCreditModel, error) {
+	var credit *models.BoostCreditModel
+
+	err := repo.db.Where("user_id = ?", userId).First(&credit).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return credit, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *boostRepository) AddCredits(userId string, credits int, tx *gorm.DB) error {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"balance":    gorm.Expr("boost_credits.balance + ?", credits),
+			"updated_at": tNow,
+		}),
+	}).Create(&models.BoostCreditModel{
+		UserId:  userId,
+		Balance: credits,
+	}).Error
+}
+
+// UseCredit takes one credit from the balance, it returns nil when no credit
+// is left. The balance row stays locked until the transaction ends, so
+// concurrent boosts of the same user run one after another.
+func (repo *boostRepository) UseCredit(userId string, tx *gorm.DB) (*models.BoostCreditModel, error) {
+	var credit models.BoostCreditModel
+
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+	result := tx.Model(&credit).
+		Clauses(clause.Returning{}).
+		Where("user_id = ? AND balance > 0", userId).
+		Updates(map[string]interface{}{
+			"balance":    gorm.Expr("balance - 1"),
+			"updated_at": tNow,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &credit, nil
+}
+
+func (repo *boostRepository) CreateBoost(model *models.BoostModel, tx *gorm.DB) (*models.BoostModel, error) {
+	err := tx.Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (repo *boostRepository) GetDetailBoost(whereClause interface{}) (*models.BoostModel, error) {
+	var boost *models.BoostModel
+
+	err := repo.db.Where(whereClause).First(&boost).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return boost, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *boostRepository) GetListBoost(ids []string) ([]*models.BoostModel, error) {
+	boosts := []*models.BoostModel{}
+
+	err := repo.db.Where("id IN ?", ids).Find(&boosts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return boosts, nil
+}
+
+func (repo *boostRepository) GetActiveBoost(userId string, tx *gorm.DB) (*models.BoostModel, error) {
+	var boost *models.BoostModel
+
+	err := tx.Where("user_id = ? AND end_at > ?", userId, time.Now().UTC().Format("2006-01-02 15:04:05")).
+		Order("end_at DESC").
+		First(&boost).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return boost, nil
+	default:
+		return nil, err
+	}
+}
+
+// CreateImpressions records the viewers of boosted users, a viewer already
+// counted for the boost is skipped.
+func (repo *boostRepository) CreateImpressions(impressions []*models.BoostImpressionModel) error {
+	return repo.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&impressions).Error
+}
+
+func (repo *boostRepository) CountImpressions(boostId string) (int64, error) {
+	var count int64
+
+	err := repo.db.Model(&models.BoostImpressionModel{}).Where("boost_id = ?", boostId).Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountLikes counts the right swipes the boosted user got while the boost was
+// active.
+func (repo *boostRepository) CountLikes(boost *models.BoostModel) (int64, error) {
+	var count int64
+
+	err := repo.db.Model(&models.SwipeModel{}).
+		Where("target_id = ? AND direction = ?", boost.UserId, models.SwipeRight).
+		Where("created_at >= ? AND created_at < ?", boost.StartAt, boost.EndAt).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
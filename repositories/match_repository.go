@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MatchRepositoryInterface interface {
+	CreateMatch(model *models.MatchModel, tx *gorm.DB) (*models.MatchModel, error)
+	GetDetailMatch(whereClause interface{}, relations []string) (*models.MatchModel, error)
+	GetListMatch(meta *requests.MetaPaginationRequest, userId string, relations []string) ([]*models.MatchModel, int64, error)
+	DeleteMatch(model *models.MatchModel, tx *gorm.DB) error
+	LockPair(userOneId, userTwoId string, tx *gorm.DB) error
+}
+
+type matchRepository struct {
+	db *gorm.DB
+}
+
+func NewMatchRepository(db *gorm.DB) MatchRepositoryInterface {
+	return &matchRepository{
+		db: db,
+	}
+}
+
+func (repo *matchRepository) CreateMatch(model *models.MatchModel, tx *gorm.DB) (*models.MatchModel, error) {
+	err := tx.Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (repo *matchRepository) GetDetailMatch(whereClause interface{}, relations []string) (*models.MatchModel, error) {
+	var match *models.MatchModel
+
+	queryBuilder := repo.db.Where(whereClause).Where("deleted_at is null")
+	for _, relation := range relations {
+		queryBuilder.Preload(relation)
+	}
+
+	err := queryBuilder.First(&match).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return match, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *matchRepository) GetListMatch(meta *requests.MetaPaginationRequest, userId string, relations []string) ([]*models.MatchModel, int64, error) {
+	var matches []*models.MatchModel
+
+	queryBuilder := repo.db.Model(&models.MatchModel{}).
+		Where("(user_one_id = ? OR user_two_id = ?)", userId, userId).
+		Where("deleted_at is null")
+
+	var totalRows int64
+	if err := queryBuilder.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for _, relation := range relations {
+		queryBuilder.Preload(relation)
+	}
+
+	queryBuilder.Limit(meta.Limit).Offset(meta.Offset).Order(fmt.Sprintf("created_at %s", meta.Order))
+
+	if err := queryBuilder.Find(&matches).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return matches, totalRows, nil
+}
+
+func (repo *matchRepository) DeleteMatch(model *models.MatchModel, tx *gorm.DB) error {
+	err := tx.Model(&models.MatchModel{}).Where("id = ?", model.Id).Update("deleted_at", time.Now().UTC().Format("2006-01-02 15:04:05")).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LockPair serializes the swipes of a pair until the transaction ends, so two
+// users liking each other at the same time still see the like of the other.
+// The ids are expected in the order of models.MatchPair.
+func (repo *matchRepository) LockPair(userOneId, userTwoId string, tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "match:"+userOneId+":"+userTwoId).Error
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepositoryInterface interface {
+	CreateNotification(model *models.NotificationModel, tx *gorm.DB) (*models.NotificationModel, error)
+	GetListNotification(meta *requests.MetaPaginationRequest, userId string) ([]*models.NotificationModel, int64, error)
+	ReadNotification(id string, userId string, tx *gorm.DB) (bool, error)
+	DeleteNotificationByReference(notificationType string, referenceId string, tx *gorm.DB) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepositoryInterface {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (repo *notificationRepository) CreateNotification(model *models.NotificationModel, tx *gorm.DB) (*models.NotificationModel, error) {
+	err := tx.Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (repo *notificationRepository) GetListNotification(meta *requests.MetaPaginationRequest, userId string) ([]*models.NotificationModel, int64, error) {
+	var notifications []*models.NotificationModel
+
+	queryBuilder := repo.db.Model(&models.NotificationModel{}).Where("user_id = ?", userId)
+
+	var totalRows int64
+	if err := queryBuilder.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	queryBuilder.Limit(meta.Limit).Offset(meta.Offset).Order(fmt.Sprintf("created_at %s", meta.Order))
+
+	if err := queryBuilder.Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, totalRows, nil
+}
+
+// ReadNotification marks the notification as read, it returns false when the
+// user has no such notification.
+func (repo *notificationRepository) ReadNotification(id string, userId string, tx *gorm.DB) (bool, error) {
+	result := tx.Model(&models.NotificationModel{}).
+		Where("id = ? AND user_id = ?", id, userId).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now().UTC().Format("2006-01-02 15:04:05")))
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteNotificationByReference removes the notifications about something that
+// was undone, e.g. a rewound super like.
+func (repo *notificationRepository) DeleteNotificationByReference(notificationType string, referenceId string, tx *gorm.DB) error {
+	return tx.Where("type = ? AND reference_id = ?", notificationType, referenceId).Delete(&models.NotificationModel{}).Error
+}
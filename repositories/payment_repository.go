@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"dating-app-api/entities/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepositoryInterface interface {
+	CreatePayment(model *models.PaymentModel, tx *gorm.DB) (*models.PaymentModel, error)
+	GetDetailPayment(whereClause interface{}) (*models.PaymentModel, error)
+	UpdatePayment(model *models.PaymentModel, tx *gorm.DB) (*models.PaymentModel, error)
+	CreateTransaction(model *models.PaymentTransactionModel, tx *gorm.DB) (bool, error)
+	GetDetailTransaction(whereClause interface{}) (*models.PaymentTransactionModel, error)
+	UpdateTransaction(model *models.PaymentTransactionModel, tx *gorm.DB) (*models.PaymentTransactionModel, error)
+	CreateEvent(model *models.PaymentEventModel, tx *gorm.DB) error
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepositoryInterface {
+	return &paymentRepository{
+		db: db,
+	}
+}
+
+func (repo *paymentRepository) CreatePayment(model *models.PaymentModel, tx *gorm.DB) (*models.PaymentModel, error) {
+	err := tx.Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (repo *paymentRepository) GetDetailPayment(whereClause interface{}) (*models.PaymentModel, error) {
+	var payment *models.PaymentModel
+
+	err := repo.db.Where(whereClause).Order("created_at desc").First(&payment).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return payment, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *paymentRepository) UpdatePayment(model *models.PaymentModel, tx *gorm.DB) (*models.PaymentModel, error) {
+	err := tx.Where("id = ?", model.Id).Updates(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// CreateTransaction claims the gateway transaction id, it returns false when
+// the same transaction id was already stored by an earlier callback.
+func (repo *paymentRepository) CreateTransaction(model *models.PaymentTransactionModel, tx *gorm.DB) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "transaction_id"}},
+		DoNothing: true,
+	}).Create(&model)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (repo *paymentRepository) GetDetailTransaction(whereClause interface{}) (*models.PaymentTransactionModel, error) {
+	var transaction *models.PaymentTransactionModel
+
+	err := repo.db.Where(whereClause).First(&transaction).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return transaction, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *paymentRepository) UpdateTransaction(model *models.PaymentTransactionModel, tx *gorm.DB) (*models.PaymentTransactionModel, error) {
+	err := tx.Where("id = ?", model.Id).Updates(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (repo *paymentRepository) CreateEvent(model *models.PaymentEventModel, tx *gorm.DB) error {
+	return tx.Create(&model).Error
+}
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PhotoRepositoryInterface interface {
+	CreatePhoto(model *models.PhotoModel, tx *gorm.DB) (*models.PhotoModel, error)
+	GetDetailPhoto(whereClause interface{}) (*models.PhotoModel, error)
+	GetListPhoto(userId string) ([]*models.PhotoModel, error)
+	UpdatePhotoPositions(userId string, photoIds []string, tx *gorm.DB) error
+	SetPrimaryPhoto(userId string, photoId string, tx *gorm.DB) error
+	DeletePhoto(model *models.PhotoModel, tx *gorm.DB) error
+	LockUserPhotos(userId string, tx *gorm.DB) error
+	FinishPhotoProcessing(model *models.PhotoModel, tx *gorm.DB) (bool, error)
+	FailStalePhotos(before time.Time, reason string, tx *gorm.DB) ([]*models.PhotoModel, error)
+	GetListModerationPhoto(meta *requests.MetaPaginationRequest) ([]*models.PhotoModel, int64, error)
+	ModeratePhoto(model *models.PhotoModel, tx *gorm.DB) (bool, error)
+}
+
+type photoRepository struct {
+	db *gorm.DB
+}
+
+func NewPhotoRepository(db *gorm.DB) PhotoRepositoryInterface {
+	return &photoRepository{
+		db: db,
+	}
+}
+
+func (repo *photoRepository) CreatePhoto(model *models.PhotoModel, tx *gorm.DB) (*models.PhotoModel, error) {
+	err := tx.Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (repo *photoRepository) GetDetailPhoto(whereClause interface{}) (*models.PhotoModel, error) {
+	var photo *models.PhotoModel
+
+	err := repo.db.Where(whereClause).First(&photo).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return photo, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *photoRepository) GetListPhoto(userId string) ([]*models.PhotoModel, error) {
+	var photos []*models.PhotoModel
+
+	if err := repo.db.Where("user_id = ?", userId).Order("position asc").Find(&photos).Error; err != nil {
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+// UpdatePhotoPositions stores the order of photoIds as the photo positions.
+func (repo *photoRepository) UpdatePhotoPositions(userId string, photoIds []string, tx *gorm.DB) error {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+	for position, photoId := range photoIds {
+		err := tx.Model(&models.PhotoModel{}).
+			Where("id = ? AND user_id = ?", photoId, userId).
+			Updates(map[string]interface{}{
+				"position":   position,
+				"updated_at": tNow,
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *photoRepository) SetPrimaryPhoto(userId string, photoId string, tx *gorm.DB) error {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	err := tx.Model(&models.PhotoModel{}).
+		Where("user_id = ? AND is_primary", userId).
+		Updates(map[string]interface{}{
+			"is_primary": false,
+			"updated_at": tNow,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&models.PhotoModel{}).
+		Where("id = ? AND user_id = ?", photoId, userId).
+		Updates(map[string]interface{}{
+			"is_primary": true,
+			"updated_at": tNow,
+		}).Error
+}
+
+func (repo *photoRepository) DeletePhoto(model *models.PhotoModel, tx *gorm.DB) error {
+	return tx.Where("id = ?", model.Id).Delete(&models.PhotoModel{}).Error
+}
+
+// LockUserPhotos serializes the uploads of the user until the transaction
+// ends, so the photo limit is checked against every committed upload.
+func (repo *photoRepository) LockUserPhotos(userId string, tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "photos:"+userId).Error
+}
+
+// FinishPhotoProcessing stores the processing result, it returns false when
+// the photo is no longer waiting for it (e.g. deleted in the meantime).
+func (repo *photoRepository) FinishPhotoProcessing(model *models.PhotoModel, tx *gorm.DB) (bool, error) {
+	result := tx.Where("id = ? AND status = ?", model.Id, models.PhotoProcessing).Updates(&model)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// FailStalePhotos marks the photos still processing since before as failed and
+// returns them.
+func (repo *photoRepository) FailStalePhotos(before time.Time, reason string, tx *gorm.DB) ([]*models.PhotoModel, error) {
+	var photos []*models.PhotoModel
+
+	err := tx.Model(&photos).
+		Clauses(clause.Returning{}).
+		Where("status = ? AND created_at < ?", models.PhotoProcessing, before.UTC().Format("2006-01-02 15:04:05")).
+		Updates(map[string]interface{}{
+			"status":          models.PhotoFailed,
+			"rejected_reason": reason,
+			"updated_at":      time.Now().UTC().Format("2006-01-02 15:04:05"),
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+// GetListModerationPhoto lists the processed photos waiting for a moderation
+// decision, oldest first unless the order says otherwise.
+func (repo *photoRepository) GetListModerationPhoto(meta *requests.MetaPaginationRequest) ([]*models.PhotoModel, int64, error) {
+	var photos []*models.PhotoModel
+
+	queryBuilder := repo.db.Model(&models.PhotoModel{}).
+		Where("status = ? AND moderation_status = ?", models.PhotoReady, models.ModerationPending)
+
+	var totalRows int64
+	if err := queryBuilder.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	queryBuilder.Limit(meta.Limit).Offset(meta.Offset).Order(fmt.Sprintf("created_at %s", meta.Order))
+
+	if err := queryBuilder.Find(&photos).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return photos, totalRows, nil
+}
+
+// ModeratePhoto stores the moderation decision, it returns false when the
+// photo is not pending anymore.
+func (repo *photoRepository) ModeratePhoto(model *models.PhotoModel, tx *gorm.DB) (bool, error) {
+	result := tx.Where("id = ? AND moderation_status = ?", model.Id, models.ModerationPending).Updates(&model)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
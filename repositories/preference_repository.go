@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"dating-app-api/entities/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PreferenceRepositoryInterface interface {
+	GetDetailPreference(userId string) (*models.PreferenceModel, error)
+	CreatePreference(model *models.PreferenceModel, tx *gorm.DB) (*models.PreferenceModel, error)
+	UpsertPreference(model *models.PreferenceModel, tx *gorm.DB) (*models.PreferenceModel, error)
+}
+
+type preferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewPreferenceRepository(db *gorm.DB) PreferenceRepositoryInterface {
+	return &preferenceRepository{
+		db: db,
+	}
+}
+
+func (repo *preferenceRepository) GetDetailPreference(userId string) (*models.PreferenceModel, error) {
+	var preference *models.PreferenceModel
+
+	err := repo.db.Where("user_id = ?", userId).First(&preference).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return preference, nil
+	default:
+		return nil, err
+	}
+}
+
+// CreatePreference stores the preferences unless the user already has them.
+func (repo *preferenceRepository) CreatePreference(model *models.PreferenceModel, tx *gorm.DB) (*models.PreferenceModel, error) {
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoNothing: true,
+	}).Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (repo *preferenceRepository) UpsertPreference(model *models.PreferenceModel, tx *gorm.DB) (*models.PreferenceModel, error) {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+	model.UpdatedAt = &tNow
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"min_age", "max_age", "genders", "max_distance",
+			"min_height", "max_height", "require_photos", "updated_at",
+		}),
+	}).Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
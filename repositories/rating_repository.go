@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/utils/rating"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RatingRepositoryInterface interface {
+	GetRatings(userIds []string) (map[string]float64, error)
+	SaveRating(userId string, value float64) error
+	ReplaceRatings(userRatings []*models.UserRatingModel, tx *gorm.DB) error
+	GetRatingHistogram(bucketSize float64) ([]*models.RatingBucket, error)
+}
+
+type ratingRepository struct {
+	db *gorm.DB
+}
+
+func NewRatingRepository(db *gorm.DB) RatingRepositoryInterface {
+	return &ratingRepository{
+		db: db,
+	}
+}
+
+func (repo *ratingRepository) GetRatings(userIds []string) (map[string]float64, error) {
+	var userRatings []*models.UserRatingModel
+
+	err := repo.db.Where("user_id IN ?", userIds).Find(&userRatings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := map[string]float64{}
+	for _, userRating := range userRatings {
+		ratings[userRating.UserId] = userRating.Rating
+	}
+
+	return ratings, nil
+}
+
+// SaveRating stores the rating after one more swipe on the user, it runs
+// outside of any transaction from the background updater.
+func (repo *ratingRepository) SaveRating(userId string, value float64) error {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	return repo.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"rating":       value,
+			"swipes_count": gorm.Expr("user_ratings.swipes_count + 1"),
+			"updated_at":   tNow,
+		}),
+	}).Create(&models.UserRatingModel{
+		UserId:      userId,
+		Rating:      value,
+		SwipesCount: 1,
+	}).Error
+}
+
+// ReplaceRatings swaps every stored rating with the given ones.
+func (repo *ratingRepository) ReplaceRatings(userRatings []*models.UserRatingModel, tx *gorm.DB) error {
+	err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.UserRatingModel{}).Error
+	if err != nil {
+		return err
+	}
+
+	if len(userRatings) == 0 {
+		return nil
+	}
+
+	return tx.CreateInBatches(userRatings, 500).Error
+}
+
+// GetRatingHistogram counts the users per rating bucket, users without a
+// stored rating count with the initial one.
+func (repo *ratingRepository) GetRatingHistogram(bucketSize float64) ([]*models.RatingBucket, error) {
+	var buckets []*models.RatingBucket
+
+	err := repo.db.Model(&models.UserModel{}).
+		Select("floor(COALESCE(user_ratings.rating, ?) / ?) * ? AS floor, count(*) AS count", rating.Initial, bucketSize, bucketSize).
+		Joins("LEFT JOIN user_ratings ON user_ratings.user_id = users.id").
+		Where("users.deleted_at is null").
+		Group("floor").
+		Order("floor").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"dating-app-api/entities/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SubscriptionRepositoryInterface interface {
+	GetDetailPlan(whereClause interface{}) (*models.PlanModel, error)
+	GetListPlan() ([]*models.PlanModel, error)
+	CreateSubscription(model *models.SubscriptionModel, tx *gorm.DB) (*models.SubscriptionModel, error)
+	GetActiveSubscription(userId string) (*models.SubscriptionModel, error)
+	GetLastSubscription(userId string) (*models.SubscriptionModel, error)
+	ExpireSubscriptions(tx *gorm.DB) ([]string, error)
+}
+
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepositoryInterface {
+	return &subscriptionRepository{
+		db: db,
+	}
+}
+
+func (repo *subscriptionRepository) GetDetailPlan(whereClause interface{}) (*models.PlanModel, error) {
+	var plan *models.PlanModel
+
+	err := repo.db.Where(whereClause).First(&plan).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return plan, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *subscriptionRepository) GetListPlan() ([]*models.PlanModel, error) {
+	var plans []*models.PlanModel
+
+	if err := repo.db.Order("price asc").Find(&plans).Error; err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+func (repo *subscriptionRepository) CreateSubscription(model *models.SubscriptionModel, tx *gorm.DB) (*models.SubscriptionModel, error) {
+	err := tx.Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// GetActiveSubscription returns the subscription running now, a stacked
+// renewal only counts once it started.
+func (repo *subscriptionRepository) GetActiveSubscription(userId string) (*models.SubscriptionModel, error) {
+	var subscription *models.SubscriptionModel
+
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	err := repo.db.Preload("Plan").
+		Where("user_id = ? AND status = ? AND start_at <= ? AND end_at > ?", userId, models.SubscriptionActive, now, now).
+		Order("end_at desc").
+		First(&subscription).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return subscription, nil
+	default:
+		return nil, err
+	}
+}
+
+// GetLastSubscription returns the subscription that ends last, running or
+// stacked, a renewal starts when it ends.
+func (repo *subscriptionRepository) GetLastSubscription(userId string) (*models.SubscriptionModel, error) {
+	var subscription *models.SubscriptionModel
+
+	err := repo.db.
+		Where("user_id = ? AND status = ? AND end_at > ?", userId, models.SubscriptionActive, time.Now().UTC().Format("2006-01-02 15:04:05")).
+		Order("end_at desc").
+		First(&subscription).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return subscription, nil
+	default:
+		return nil, err
+	}
+}
+
+// ExpireSubscriptions marks every ended subscription as expired and downgrades
+// the users left without any active subscription, it returns their ids.
+func (repo *subscriptionRepository) ExpireSubscriptions(tx *gorm.DB) ([]string, error) {
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	var userIds []string
+	err := tx.Model(&models.SubscriptionModel{}).
+		Where("status = ? AND end_at <= ?", models.SubscriptionActive, now).
+		Distinct().
+		Pluck("user_id", &userIds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userIds) == 0 {
+		return userIds, nil
+	}
+
+	err = tx.Model(&models.SubscriptionModel{}).
+		Where("status = ? AND end_at <= ?", models.SubscriptionActive, now).
+		Updates(map[string]interface{}{
+			"status":     models.SubscriptionExpired,
+			"updated_at": now,
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Table("users").
+		Where("id IN ?", userIds).
+		Where("NOT EXISTS (SELECT 1 FROM subscriptions s WHERE s.user_id = users.id AND s.status = ? AND s.end_at > ?)", models.SubscriptionActive, now).
+		Update("verified", false).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return userIds, nil
+}
@@ -1,7 +1,203 @@
 package repositories
 
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
 type SwipeRepositoryInterface interface {
-	SaveLikes()
-	SavePass()
-	FindLikes()
+	SaveLikes(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error)
+	SavePass(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error)
+	SaveSuperLikes(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error)
+	CountSuperLikes(swiperId string, since time.Time) (int64, error)
+	FindLikes(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error)
+	FindSwipe(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error)
+	GetLastSwipe(swiperId string, tx *gorm.DB) (*models.SwipeModel, error)
+	DeleteSwipe(model *models.SwipeModel, tx *gorm.DB) error
+	SavePassPair(userId, otherUserId string, tx *gorm.DB) error
+	EachSwipe(fn func(swipe *models.SwipeModel) error) error
+	GetListReceivedLikes(meta *requests.MetaPaginationRequest, userId string, relations []string) ([]*models.SwipeModel, int64, error)
+}
+
+type swipeRepository struct {
+	db *gorm.DB
+}
+
+func NewSwipeRepository(db *gorm.DB) SwipeRepositoryInterface {
+	return &swipeRepository{
+		db: db,
+	}
+}
+
+func (repo *swipeRepository) SaveLikes(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error) {
+	return repo.saveSwipe(swiperId, targetId, models.SwipeRight, false, tx)
+}
+
+func (repo *swipeRepository) SavePass(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error) {
+	return repo.saveSwipe(swiperId, targetId, models.SwipeLeft, false, tx)
+}
+
+func (repo *swipeRepository) SaveSuperLikes(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error) {
+	return repo.saveSwipe(swiperId, targetId, models.SwipeRight, true, tx)
+}
+
+// saveSwipe keeps one row per (swiper, target) pair, a repeated swipe
+// replaces the previous direction and remembers it in previous_direction. The
+// row is upserted so concurrent swipes on the same pair can not both insert,
+// the returned id is the one of the stored row.
+func (repo *swipeRepository) saveSwipe(swiperId, targetId, direction string, isSuper bool, tx *gorm.DB) (*models.SwipeModel, error) {
+	swipe := &models.SwipeModel{
+		SwiperId:  swiperId,
+		TargetId:  targetId,
+		Direction: direction,
+		IsSuper:   isSuper,
+	}
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "swiper_id"}, {Name: "target_id"}},
+		DoUpdates: append(clause.AssignmentColumns([]string{"direction", "is_super", "created_at"}), clause.Assignment{
+			Column: clause.Column{Name: "previous_direction"},
+			Value:  gorm.Expr("swipes.direction"),
+		}),
+	}, clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "previous_direction"}}}).Create(&swipe).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return swipe, nil
+}
+
+func (repo *swipeRepository) FindLikes(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error) {
+	var swipe *models.SwipeModel
+
+	err := tx.Where("swiper_id = ? AND target_id = ? AND direction = ?", swiperId, targetId, models.SwipeRight).First(&swipe).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return swipe, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *swipeRepository) FindSwipe(swiperId, targetId string, tx *gorm.DB) (*models.SwipeModel, error) {
+	var swipe *models.SwipeModel
+
+	err := tx.Where("swiper_id = ? AND target_id = ?", swiperId, targetId).First(&swipe).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return swipe, nil
+	default:
+		return nil, err
+	}
+}
+
+// CountSuperLikes counts the super likes the swiper made from since on.
+func (repo *swipeRepository) CountSuperLikes(swiperId string, since time.Time) (int64, error) {
+	var count int64
+
+	err := repo.db.Model(&models.SwipeModel{}).
+		Where("swiper_id = ? AND is_super AND created_at >= ?", swiperId, since.UTC().Format("2006-01-02 15:04:05")).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetLastSwipe locks the most recent swipe of the swiper until the transaction
+// ends, so the same swipe can not be rewound twice at once.
+func (repo *swipeRepository) GetLastSwipe(swiperId string, tx *gorm.DB) (*models.SwipeModel, error) {
+	var swipe *models.SwipeModel
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("swiper_id = ?", swiperId).
+		Order("created_at DESC").
+		First(&swipe).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return swipe, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *swipeRepository) DeleteSwipe(model *models.SwipeModel, tx *gorm.DB) error {
+	return tx.Where("id = ?", model.Id).Delete(&models.SwipeModel{}).Error
+}
+
+// SavePassPair turns the swipes of both users on each other into passes, so
+// an unmatched pair only matches again when both swipe right again. The
+// replaced direction is kept like for a repeated swipe.
+func (repo *swipeRepository) SavePassPair(userId, otherUserId string, tx *gorm.DB) error {
+	return tx.Model(&models.SwipeModel{}).
+		Where("(swiper_id = ? AND target_id = ?) OR (swiper_id = ? AND target_id = ?)", userId, otherUserId, otherUserId, userId).
+		Updates(map[string]interface{}{
+			"previous_direction": gorm.Expr("direction"),
+			"direction":          models.SwipeLeft,
+			"is_super":           false,
+		}).Error
+}
+
+// EachSwipe streams every swipe oldest first without loading them all in
+// memory, fn returning an error stops the iteration.
+func (repo *swipeRepository) EachSwipe(fn func(swipe *models.SwipeModel) error) error {
+	rows, err := repo.db.Model(&models.SwipeModel{}).Order("created_at, id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var swipe models.SwipeModel
+		if err := repo.db.ScanRows(rows, &swipe); err != nil {
+			return err
+		}
+		if err := fn(&swipe); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetListReceivedLikes lists the right swipes on the user the user did not
+// swipe back on yet, either way.
+func (repo *swipeRepository) GetListReceivedLikes(meta *requests.MetaPaginationRequest, userId string, relations []string) ([]*models.SwipeModel, int64, error) {
+	var swipes []*models.SwipeModel
+
+	queryBuilder := repo.db.Model(&models.SwipeModel{}).
+		Joins("JOIN users ON users.id = swipes.swiper_id AND users.deleted_at is null").
+		Where("swipes.target_id = ? AND swipes.direction = ?", userId, models.SwipeRight).
+		Where("NOT EXISTS (SELECT 1 FROM swipes back WHERE back.swiper_id = swipes.target_id AND back.target_id = swipes.swiper_id)")
+
+	// counted on a copy, gorm keeps the joins of the count in the statement
+	var totalRows int64
+	if err := queryBuilder.Session(&gorm.Session{}).Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	queryBuilder.Select("swipes.*")
+	for _, relation := range relations {
+		queryBuilder.Preload(relation)
+	}
+
+	queryBuilder.Limit(meta.Limit).Offset(meta.Offset).Order(fmt.Sprintf("swipes.created_at %s", meta.Order))
+
+	if err := queryBuilder.Find(&swipes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return swipes, totalRows, nil
 }
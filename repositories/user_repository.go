@@ -3,16 +3,24 @@ package repositories
 import (
 	"dating-app-api/entities/models"
 	"dating-app-api/entities/requests"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepositoryInterface interface {
 	CreateUser(model *models.UserModel, tx *gorm.DB) (*models.UserModel, error)
 	GetDetailUser(whereClause interface{}, whereNotClause interface{}, orClause interface{}, relations []string) (*models.UserModel, error)
-	GetListUser(meta *requests.MetaPaginationRequest, whereClause interface{}, whereNotClause interface{}, orClause interface{}, relations []string) ([]*models.UserModel, int64, error)
+	GetListUser(meta *requests.MetaPaginationRequest, filter *requests.DiscoverFilter, relations []string) ([]*models.UserModel, int64, error)
 	UpdateUser(model *models.UserModel, tx *gorm.DB) (*models.UserModel, error)
 	DeleteUser(model *models.UserModel, tx *gorm.DB) error
+	GetDetailProfile(userId string) (*models.ProfileModel, error)
+	UpsertProfile(model *models.ProfileModel, tx *gorm.DB) (*models.ProfileModel, error)
+	GetDetailLocation(userId string) (*models.UserLocationModel, error)
+	UpsertLocation(model *models.UserLocationModel, tx *gorm.DB) (*models.UserLocationModel, error)
+	TouchLastActive(userId string) error
 }
 
 type userReposiotry struct {
@@ -46,6 +54,10 @@ func (repo *userReposiotry) GetDetailUser(whereClause interface{}, whereNotClaus
 		queryBuilder.Or(orClause)
 	}
 
+	for _, relation := range relations {
+		queryBuilder.Preload(relation)
+	}
+
 	err := queryBuilder.First(&user).Error
 	switch err {
 	case gorm.ErrRecordNotFound:
@@ -57,36 +69,114 @@ func (repo *userReposiotry) GetDetailUser(whereClause interface{}, whereNotClaus
 	}
 }
 
-func (repo *userReposiotry) GetListUser(meta *requests.MetaPaginationRequest, whereClause interface{}, whereNotClause interface{}, orClause interface{}, relations []string) ([]*models.UserModel, int64, error) {
+func (repo *userReposiotry) GetListUser(meta *requests.MetaPaginationRequest, filter *requests.DiscoverFilter, relations []string) ([]*models.UserModel, int64, error) {
 
 	var users []*models.UserModel
 
-	queryBuilder := repo.db.Debug().Table("users")
+	queryBuilder := repo.db.Table("users").
+		Joins("LEFT JOIN user_locations ON user_locations.user_id = users.id").
+		Where("users.deleted_at is null")
 
-	if whereClause != nil {
-		queryBuilder.Where(whereClause)
+	if len(filter.ExcludeIds) > 0 {
+		queryBuilder.Where("users.id NOT IN ?", filter.ExcludeIds)
 	}
 
-	if whereNotClause != nil {
-		queryBuilder.Not(whereNotClause)
+	if filter.OnlyWithProfile || filter.Preference != nil {
+		queryBuilder.Joins("JOIN profiles ON profiles.user_id = users.id")
 	}
 
-	if orClause != nil {
-		queryBuilder.Or(orClause)
+	// what the viewer looks for in the candidates
+	if preference := filter.Preference; preference != nil {
+		now := time.Now().UTC()
+		queryBuilder.
+			Where("profiles.birthdate > ? AND profiles.birthdate <= ?",
+				now.AddDate(-preference.MaxAge-1, 0, 0).Format("2006-01-02"),
+				now.AddDate(-preference.MinAge, 0, 0).Format("2006-01-02")).
+			Where("profiles.gender IN ?", preference.Genders)
+
+		if preference.MinHeight != nil {
+			queryBuilder.Where("profiles.height >= ?", *preference.MinHeight)
+		}
+
+		if preference.MaxHeight != nil {
+			queryBuilder.Where("profiles.height <= ?", *preference.MaxHeight)
+		}
+
+		if preference.RequirePhotos {
+			queryBuilder.Where("EXISTS (SELECT 1 FROM photos WHERE photos.user_id = users.id AND photos.status = ? AND photos.moderation_status = ?)", models.PhotoReady, models.ModerationApproved)
+		}
 	}
 
-	if len(relations) > 0 {
-		for _, relation := range relations {
-			queryBuilder.Preload(relation)
+	// what the candidates look for in the viewer
+	if viewer := filter.Viewer; viewer != nil {
+		queryBuilder.Joins("JOIN preferences ON preferences.user_id = users.id").
+			Where("? BETWEEN preferences.min_age AND preferences.max_age", viewer.Age).
+			Where("? = ANY (preferences.genders)", viewer.Gender).
+			Where("(NOT preferences.require_photos OR EXISTS (SELECT 1 FROM photos WHERE photos.user_id = ? AND photos.status = ? AND photos.moderation_status = ?))", viewer.Id, models.PhotoReady, models.ModerationApproved)
+
+		if viewer.Height != nil {
+			queryBuilder.
+				Where("(preferences.min_height IS NULL OR preferences.min_height <= ?)", *viewer.Height).
+				Where("(preferences.max_height IS NULL OR preferences.max_height >= ?)", *viewer.Height)
+		} else {
+			queryBuilder.Where("preferences.min_height IS NULL AND preferences.max_height IS NULL")
+		}
+
+		// without a known distance a candidate limiting the distance is left out
+		if filter.Origin != nil {
+			queryBuilder.Where("(preferences.max_distance IS NULL OR earth_distance(ll_to_earth(?, ?), ll_to_earth(user_locations.latitude, user_locations.longitude)) <= preferences.max_distance * 1000)", filter.Origin.Latitude, filter.Origin.Longitude)
+		} else {
+			queryBuilder.Where("preferences.max_distance IS NULL")
 		}
 	}
 
+	if filter.Origin != nil && filter.MaxDistanceKm > 0 {
+		// the earth_box check uses the gist index, it is a square so the exact
+		// distance is checked as well
+		radius := float64(filter.MaxDistanceKm) * 1000
+		queryBuilder.
+			Where("earth_box(ll_to_earth(?, ?), ?) @> ll_to_earth(user_locations.latitude, user_locations.longitude)", filter.Origin.Latitude, filter.Origin.Longitude, radius).
+			Where("earth_distance(ll_to_earth(?, ?), ll_to_earth(user_locations.latitude, user_locations.longitude)) <= ?", filter.Origin.Latitude, filter.Origin.Longitude, radius)
+	}
+
+	// counted on a copy, gorm keeps the joins of the count in the statement
 	var totalRows int64
-	if err := queryBuilder.Where("deleted_at is null").Count(&totalRows).Error; err != nil {
+	if err := queryBuilder.Session(&gorm.Session{}).Count(&totalRows).Error; err != nil {
 		return nil, 0, err
 	}
 
-	queryBuilder.Limit(meta.Limit).Offset(meta.Offset).Order(meta.Order)
+	// the rating is null for users nobody swiped on yet
+	columns := []string{"users.*", "(SELECT user_ratings.rating FROM user_ratings WHERE user_ratings.user_id = users.id) AS rating"}
+	args := []interface{}{}
+	if filter.Origin != nil {
+		columns = append(columns, "earth_distance(ll_to_earth(?, ?), ll_to_earth(user_locations.latitude, user_locations.longitude)) / 1000 AS distance")
+		args = append(args, filter.Origin.Latitude, filter.Origin.Longitude)
+	}
+
+	// candidates who super liked the viewer come before everyone else
+	if filter.Viewer != nil {
+		columns = append(columns, "EXISTS (SELECT 1 FROM swipes WHERE swipes.swiper_id = users.id AND swipes.target_id = ? AND swipes.is_super) AS super_liked")
+		args = append(args, filter.Viewer.Id)
+		queryBuilder.Order("super_liked DESC")
+	}
+
+	// boosted candidates come right after super likes, so they make it into
+	// the first pool however long ago they were active
+	if len(filter.BoostedIds) > 0 {
+		columns = append(columns, "users.id IN ? AS boosted")
+		args = append(args, filter.BoostedIds)
+		queryBuilder.Order("boosted DESC")
+	}
+	queryBuilder.Select(strings.Join(columns, ", "), args...)
+
+	for _, relation := range relations {
+		queryBuilder.Preload(relation)
+	}
+
+	queryBuilder.Limit(meta.Limit).Offset(meta.Offset).Order(clause.OrderByColumn{
+		Column: clause.Column{Table: "users", Name: meta.SortBy},
+		Desc:   strings.EqualFold(meta.Order, "desc"),
+	})
 
 	if err := queryBuilder.Find(&users).Error; err != nil {
 		return nil, 0, err
@@ -113,3 +203,72 @@ func (repo *userReposiotry) DeleteUser(model *models.UserModel, tx *gorm.DB) err
 	return nil
 
 }
+
+func (repo *userReposiotry) GetDetailProfile(userId string) (*models.ProfileModel, error) {
+	var profile *models.ProfileModel
+
+	err := repo.db.Where("user_id = ?", userId).First(&profile).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return profile, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *userReposiotry) UpsertProfile(model *models.ProfileModel, tx *gorm.DB) (*models.ProfileModel, error) {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+	model.UpdatedAt = &tNow
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"display_name", "birthdate", "gender", "interested_in", "bio",
+			"job", "education", "height", "location", "interests", "updated_at",
+		}),
+	}).Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+func (repo *userReposiotry) GetDetailLocation(userId string) (*models.UserLocationModel, error) {
+	var location *models.UserLocationModel
+
+	err := repo.db.Where("user_id = ?", userId).First(&location).Error
+	switch err {
+	case gorm.ErrRecordNotFound:
+		return nil, nil
+	case nil:
+		return location, nil
+	default:
+		return nil, err
+	}
+}
+
+func (repo *userReposiotry) UpsertLocation(model *models.UserLocationModel, tx *gorm.DB) (*models.UserLocationModel, error) {
+	tNow := time.Now().UTC().Format("2006-01-02 15:04:05")
+	model.UpdatedAt = &tNow
+
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"latitude", "longitude", "updated_at"}),
+	}).Create(&model).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// TouchLastActive records activity of the user for the discovery ranking, it
+// runs outside of any transaction and does not change updated_at.
+func (repo *userReposiotry) TouchLastActive(userId string) error {
+	return repo.db.Model(&models.UserModel{}).
+		Where("id = ?", userId).
+		UpdateColumn("last_active_at", time.Now().UTC().Format("2006-01-02 15:04:05")).Error
+}
@@ -10,9 +10,12 @@ import (
 	"dating-app-api/helpers"
 	"dating-app-api/repositories"
 	"dating-app-api/utils"
-	"fmt"
+	"dating-app-api/utils/tokenstore"
+	"errors"
 	"log"
+	"time"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -20,6 +23,9 @@ type AuthServiceInterface interface {
 	Login(req *requests.AuthRequest) responses.Response
 	RefreshToken(ctx context.Context) responses.Response
 	LogOut(ctx context.Context) responses.Response
+	GetListSession(ctx context.Context) responses.Response
+	RevokeSession(ctx context.Context, id string) responses.Response
+	RevokeAllSessions(ctx context.Context) responses.Response
 }
 
 type authService struct {
@@ -27,14 +33,16 @@ type authService struct {
 	common    responses.CommondResponse
 	redisUtil *utils.Redis
 	envs      *configs.EnviConfig
+	rotator   *middlewares.RefreshTokenRotator
 }
 
-func NewAuthService(userRepo repositories.UserRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig) AuthServiceInterface {
+func NewAuthService(userRepo repositories.UserRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig, rotator *middlewares.RefreshTokenRotator) AuthServiceInterface {
 	return &authService{
 		userRepo:  userRepo,
 		common:    common,
 		redisUtil: redisUtil,
 		envs:      envs,
+		rotator:   rotator,
 	}
 }
 
@@ -68,10 +76,22 @@ func (service *authService) Login(req *requests.AuthRequest) responses.Response
 		return service.common.StatusServerError("something went wrong")
 	}
 
+	session := models.SessionModel{
+		UserId:     user.Id,
+		DeviceName: req.DeviceName,
+		Ip:         req.Ip,
+		UserAgent:  req.UserAgent,
+	}
+	if err := middlewares.CreateSession(service.envs, &session); err != nil {
+		log.Println("[authService][Login] error create session :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
 	meta := models.TokenMetaData{
 		Id:     user.Id,
+		Sid:    session.Id,
 		Verify: user.Verified,
-		RtId:   "rt",
+		RtId:   uuid.NewString(),
 	}
 
 	token, err := middlewares.GenerateToken(service.envs, meta, false)
@@ -86,6 +106,10 @@ func (service *authService) Login(req *requests.AuthRequest) responses.Response
 		return service.common.StatusServerError("something went wrong")
 	}
 
+	if err := service.userRepo.TouchLastActive(user.Id); err != nil {
+		log.Println("[authService][Login] error touch last active :", err)
+	}
+
 	authResponse := responses.AuthResponse{
 		User:         &userResponse,
 		AccessToken:  token,
@@ -114,28 +138,80 @@ func (service *authService) RefreshToken(ctx context.Context) responses.Response
 		return service.common.StatusServerError("something went wrong")
 	}
 
+	session, err := service.envs.TokenStore.RetrieveSession(meta.Id, meta.Sid)
+	if err != nil {
+		log.Println("[authService][RefreshToken] error get session :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if session == nil {
+		log.Println("[authService][RefreshToken] session not found with id", meta.Sid)
+		return service.common.StatusUnAuthorize("session has been revoked")
+	}
+
+	rtId, err := service.rotator.Rotate(meta)
+	switch {
+	case errors.Is(err, middlewares.ErrRefreshTokenReused):
+		// someone else holds a copy of the token, the whole family goes
+		log.Printf("[security][refresh_token_reuse] user %v session %v last ip %v user agent %q, revoking session", meta.Id, meta.Sid, session.Ip, session.UserAgent)
+		if err := service.envs.TokenStore.Revoke(meta.Id, meta.Sid); err != nil {
+			log.Println("[authService][RefreshToken] error revoke session :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+		return service.common.StatusUnAuthorize("refresh token reused, session has been revoked")
+	case errors.Is(err, middlewares.ErrRefreshTokenRotated):
+		log.Println("[authService][RefreshToken] concurrent refresh for session", meta.Sid)
+		return service.common.StatusUnAuthorize("refresh token already used")
+	case errors.Is(err, middlewares.ErrRefreshTokenRevoked):
+		log.Println("[authService][RefreshToken] refresh token family not found for session", meta.Sid)
+		return service.common.StatusUnAuthorize("session has been revoked")
+	case err != nil:
+		log.Println("[authService][RefreshToken] error rotate refresh token :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	// the session may be revoked while it is refreshed, every write below
+	// only applies to a session that still exists so a revoke is never undone
+	session.LastUsedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	err = middlewares.RenewSession(service.envs, session)
+	switch {
+	case errors.Is(err, tokenstore.ErrSessionRevoked):
+		log.Println("[authService][RefreshToken] session revoked while refreshing", meta.Sid)
+		return service.common.StatusUnAuthorize("session has been revoked")
+	case err != nil:
+		log.Println("[authService][RefreshToken] error renew session :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
 	newMeta := models.TokenMetaData{
 		Id:     user.Id,
+		Sid:    session.Id,
 		Verify: user.Verified,
-		RtId:   "rt",
+		RtId:   rtId,
 	}
 
 	token, err := middlewares.GenerateToken(service.envs, newMeta, false)
-	if err != nil {
+	switch {
+	case errors.Is(err, tokenstore.ErrSessionRevoked):
+		log.Println("[authService][RefreshToken] session revoked while refreshing", meta.Sid)
+		return service.common.StatusUnAuthorize("session has been revoked")
+	case err != nil:
 		log.Println("[authService][RefreshToken] error generate token :", err)
 		return service.common.StatusServerError("something went wrong")
 	}
 
 	rToken, err := middlewares.GenerateToken(service.envs, newMeta, true)
-	if err != nil {
+	switch {
+	case errors.Is(err, tokenstore.ErrSessionRevoked):
+		log.Println("[authService][RefreshToken] session revoked while refreshing", meta.Sid)
+		return service.common.StatusUnAuthorize("session has been revoked")
+	case err != nil:
 		log.Println("[authService][RefreshToken] error generate token :", err)
 		return service.common.StatusServerError("something went wrong")
 	}
 
-	if err := service.redisUtil.DeleteDataFromRedis(fmt.Sprintf("metart:%v:%v", meta.Id, meta.RtId)); err != nil {
-		log.Println("[authService][RefreshToken] error delete previous token :", err)
-		return service.common.StatusServerError("something went wrong")
-
+	if err := service.userRepo.TouchLastActive(user.Id); err != nil {
+		log.Println("[authService][RefreshToken] error touch last active :", err)
 	}
 
 	authResponse := responses.AuthResponse{
@@ -149,16 +225,60 @@ func (service *authService) RefreshToken(ctx context.Context) responses.Response
 
 func (service *authService) LogOut(ctx context.Context) responses.Response {
 	meta := ctx.Value("metadata").(models.TokenMetaData)
-	err := service.redisUtil.DeleteDataFromRedis(fmt.Sprintf("metaat:%v", meta.Id))
+	err := service.envs.TokenStore.Revoke(meta.Id, meta.Sid)
+	if err != nil {
+		log.Println("[authService][LogOut] error revoke session :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(nil, nil, "logout successfully")
+}
+
+func (service *authService) GetListSession(ctx context.Context) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+	sessions, err := middlewares.RetrieveSessions(service.envs, meta.Id)
 	if err != nil {
-		log.Println("[authService][LogOut] error delete previous token :", err)
+		log.Println("[authService][GetListSession] error get list session :", err)
 		return service.common.StatusServerError("something went wrong")
 	}
-	err = service.redisUtil.DeleteDataFromRedis(fmt.Sprintf("metaat:%v:%v", meta.Id, meta.RtId))
+
+	res := make([]responses.SessionResponse, 0, len(sessions))
+	for i := range sessions {
+		res = append(res, responses.NewSessionResponse(&sessions[i], sessions[i].Id == meta.Sid))
+	}
+
+	return service.common.StatusOk(res, nil, "get list session successfully")
+}
+
+func (service *authService) RevokeSession(ctx context.Context, id string) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+	session, err := service.envs.TokenStore.RetrieveSession(meta.Id, id)
 	if err != nil {
-		log.Println("[authService][LogOut] error delete previous token :", err)
+		log.Println("[authService][RevokeSession] error get session :", err)
 		return service.common.StatusServerError("something went wrong")
 	}
 
-	return service.common.StatusOk(nil, nil, "logout successfully")
+	if session == nil {
+		log.Println("[authService][RevokeSession] session not found with id", id)
+		return service.common.StatusNotFound("session not found")
+	}
+
+	err = service.envs.TokenStore.Revoke(meta.Id, session.Id)
+	if err != nil {
+		log.Println("[authService][RevokeSession] error revoke session :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(nil, nil, "revoke session successfully")
+}
+
+func (service *authService) RevokeAllSessions(ctx context.Context) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+	err := service.envs.TokenStore.Revoke(meta.Id, tokenstore.AllSessions)
+	if err != nil {
+		log.Println("[authService][RevokeAllSessions] error revoke sessions :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(nil, nil, "logout from all devices successfully")
 }
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/utils/tokenstore"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	auth := newTestAuth(t)
+	first := auth.login(testPassword)
+
+	status, second := auth.refresh(first.RefreshToken)
+	if status != http.StatusOK {
+		t.Fatalf("refresh status = %d, want 200", status)
+	}
+
+	// within the grace window the old token lost a concurrent refresh and the
+	// session stays
+	if status, _ := auth.refresh(first.RefreshToken); status != http.StatusUnauthorized {
+		t.Fatalf("refresh within grace status = %d, want 401", status)
+	}
+	if ids, _ := auth.env.TokenStore.RetrieveSessionIds(testUserId); len(ids) != 1 {
+		t.Fatalf("sessions after a refresh within grace = %v, want the session kept", ids)
+	}
+
+	auth.clock.Advance(testGraceSeconds * time.Second)
+	if status, _ := auth.refresh(first.RefreshToken); status != http.StatusUnauthorized {
+		t.Fatalf("reused refresh status = %d, want 401", status)
+	}
+
+	ids, err := auth.env.TokenStore.RetrieveSessionIds(testUserId)
+	if err != nil {
+		t.Fatalf("retrieve session ids: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("sessions after reuse = %v, want none", ids)
+	}
+
+	// the tokens of the last rotation go with the session
+	if status, _ := auth.refresh(second.RefreshToken); status != http.StatusUnauthorized {
+		t.Fatalf("refresh with the rotated token status = %d, want 401", status)
+	}
+	if status := auth.access(second.AccessToken); status != http.StatusUnauthorized {
+		t.Fatalf("access with the rotated token status = %d, want 401", status)
+	}
+}
+
+func TestLogOutRejectsSessionTokens(t *testing.T) {
+	auth := newTestAuth(t)
+	device := auth.login(testPassword)
+	other := auth.login(testPassword)
+
+	res := auth.service.LogOut(auth.context(device.AccessToken))
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("logout status = %d (%s), want 200", res.StatusCode, res.Message)
+	}
+
+	if status := auth.access(device.AccessToken); status != http.StatusUnauthorized {
+		t.Fatalf("access after logout status = %d, want 401", status)
+	}
+	if status, _ := auth.refresh(device.RefreshToken); status != http.StatusUnauthorized {
+		t.Fatalf("refresh after logout status = %d, want 401", status)
+	}
+
+	// the other device stays signed in
+	if status, _ := auth.refresh(other.RefreshToken); status != http.StatusOK {
+		t.Fatalf("refresh of the other device status = %d, want 200", status)
+	}
+}
+
+// revokingStore runs revoke once, right after the step named at of a refresh
+type revokingStore struct {
+	tokenstore.Store
+	at     string
+	revoke func()
+}
+
+func (s *revokingStore) after(step string) {
+	if s.at == step && s.revoke != nil {
+		revoke := s.revoke
+		s.revoke = nil
+		revoke()
+	}
+}
+
+func (s *revokingStore) RotateRefreshToken(userId, sid, presented, next string, rotatedAt time.Time) (bool, *tokenstore.RefreshFamily, error) {
+	swapped, family, err := s.Store.RotateRefreshToken(userId, sid, presented, next, rotatedAt)
+	s.after("rotate")
+	return swapped, family, err
+}
+
+func (s *revokingStore) RenewSession(session *models.SessionModel, ttl time.Duration) error {
+	err := s.Store.RenewSession(session, ttl)
+	s.after("renew session")
+	return err
+}
+
+func (s *revokingStore) SaveAccessToken(meta models.TokenMetaData, ttl time.Duration) error {
+	err := s.Store.SaveAccessToken(meta, ttl)
+	s.after("save access token")
+	return err
+}
+
+func TestRefreshTokenRevokedWhileRefreshing(t *testing.T) {
+	steps := []string{"rotate", "renew session", "save access token"}
+	revokes := []struct {
+		name string
+		sid  func(auth *testAuth, device string) string
+	}{
+		{name: "logout", sid: func(auth *testAuth, device string) string {
+			return auth.context(device).Value("metadata").(models.TokenMetaData).Sid
+		}},
+		{name: "log out everywhere", sid: func(auth *testAuth, device string) string {
+			return tokenstore.AllSessions
+		}},
+	}
+
+	for _, step := range steps {
+		for _, revoke := range revokes {
+			t.Run(revoke.name+" after "+step, func(t *testing.T) {
+				auth := newTestAuth(t)
+				device := auth.login(testPassword)
+
+				store := &revokingStore{Store: auth.env.TokenStore, at: step}
+				store.revoke = func() {
+					if err := store.Store.Revoke(testUserId, revoke.sid(auth, device.AccessToken)); err != nil {
+						t.Errorf("revoke: %v", err)
+					}
+				}
+				auth.env.TokenStore = store
+
+				status, _ := auth.refresh(device.RefreshToken)
+				if status != http.StatusUnauthorized {
+					t.Fatalf("refresh status = %d, want 401", status)
+				}
+
+				// nothing of the session came back
+				sid := auth.context(device.AccessToken).Value("metadata").(models.TokenMetaData).Sid
+				if session, _ := store.RetrieveSession(testUserId, sid); session != nil {
+					t.Errorf("session = %+v, want it revoked", session)
+				}
+				if ids, _ := store.RetrieveSessionIds(testUserId); len(ids) != 0 {
+					t.Errorf("session ids = %v, want none", ids)
+				}
+				if meta, _ := store.RetrieveAccessToken(testUserId, sid); meta != nil {
+					t.Errorf("access token = %+v, want none", meta)
+				}
+				if family, _ := store.RetrieveRefreshFamily(testUserId, sid); family != nil {
+					t.Errorf("refresh family = %+v, want none", family)
+				}
+				if status := auth.access(device.AccessToken); status != http.StatusUnauthorized {
+					t.Errorf("access with the old token status = %d, want 401", status)
+				}
+			})
+		}
+	}
+}
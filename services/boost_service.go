@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/repositories"
+	"dating-app-api/utils"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const ErrorCodeBoostCreditRequired = "boost_credit_required"
+
+// activeBoostsKey is the redis sorted set of the running boosts, the members
+// are "userId:boostId" scored with the unix end of the boost.
+const activeBoostsKey = "boosts:active"
+
+type BoostServiceInterface interface {
+	Boost(ctx context.Context, tx *gorm.DB) responses.Response
+	GetDetail(ctx context.Context, id string) responses.Response
+	GetCredit(ctx context.Context) responses.Response
+	GrantCredit(request *requests.GrantBoostCreditRequest, tx *gorm.DB) responses.Response
+}
+
+type boostService struct {
+	boostRepo repositories.BoostRepositoryInterface
+	userRepo  repositories.UserRepositoryInterface
+	common    responses.CommondResponse
+	redisUtil *utils.Redis
+	envs      *configs.EnviConfig
+}
+
+func NewBoostService(boostRepo repositories.BoostRepositoryInterface, userRepo repositories.UserRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig) BoostServiceInterface {
+	return &boostService{
+		boostRepo: boostRepo,
+		userRepo:  userRepo,
+		common:    common,
+		redisUtil: redisUtil,
+		envs:      envs,
+	}
+}
+
+// Boost spends one credit to show the user first in the discovery feeds for
+// the boost duration, a user has at most one running boost.
+func (service *boostService) Boost(ctx context.Context, tx *gorm.DB) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	credit, err := service.boostRepo.UseCredit(me.Id, tx)
+	if err != nil {
+		log.Println("[boostService][Boost] error use boost credit :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if credit == nil {
+		log.Println("[boostService][Boost] no boost credit left for user", me.Id)
+		return service.common.StatusForbidden(nil, ErrorCodeBoostCreditRequired, "no boost credit left")
+	}
+
+	active, err := service.boostRepo.GetActiveBoost(me.Id, tx)
+	if err != nil {
+		log.Println("[boostService][Boost] error get active boost :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if active != nil {
+		log.Println("[boostService][Boost] boost already active for user", me.Id)
+		return service.common.StatusBadRequest(nil, "boost already active")
+	}
+
+	startAt := time.Now().UTC()
+	endAt := startAt.Add(time.Duration(service.envs.BoostMinutes) * time.Minute)
+	boost, err := service.boostRepo.CreateBoost(&models.BoostModel{
+		UserId:     me.Id,
+		Multiplier: service.envs.BoostMultiplier,
+		StartAt:    startAt.Format("2006-01-02 15:04:05"),
+		EndAt:      endAt.Format("2006-01-02 15:04:05"),
+	}, tx)
+	if err != nil {
+		log.Println("[boostService][Boost] error create boost :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	// the boost is only listed once it is committed, a rolled back boost
+	// never shows up in the feeds
+	helpers.AfterCommit(tx, func() {
+		err := service.redisUtil.RemoveSortedSetMembersFromRedis(activeBoostsKey, float64(startAt.Unix()))
+		if err != nil {
+			log.Println("[boostService][Boost] error remove ended boosts from redis :", err)
+		}
+
+		err = service.redisUtil.AddSortedSetMemberToRedis(activeBoostsKey, me.Id+":"+boost.Id, float64(endAt.Unix()))
+		if err != nil {
+			log.Println("[boostService][Boost] error save active boost to redis :", err)
+		}
+	})
+
+	boostResponse := responses.NewBoostResponse(boost, true)
+	boostResponse.CreditsLeft = &credit.Balance
+
+	return service.common.StatusCreated(boostResponse, "boost profile successfully")
+}
+
+// GetDetail returns the boost with the impressions and likes it brought, the
+// numbers keep growing until the boost ends.
+func (service *boostService) GetDetail(ctx context.Context, id string) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	boost, err := service.boostRepo.GetDetailBoost(map[string]interface{}{
+		"id":      id,
+		"user_id": me.Id,
+	})
+	if err != nil {
+		log.Println("[boostService][GetDetail] error get detail boost :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if boost == nil {
+		log.Println("[boostService][GetDetail] boost not found with id", id)
+		return service.common.StatusNotFound("boost not found")
+	}
+
+	endAt, err := helpers.ParseDateTime(boost.EndAt)
+	if err != nil {
+		log.Println("[boostService][GetDetail] error parse boost end at :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	boostResponse := responses.NewBoostResponse(boost, time.Now().Before(endAt))
+
+	boostResponse.Impressions, err = service.boostRepo.CountImpressions(boost.Id)
+	if err != nil {
+		log.Println("[boostService][GetDetail] error count impressions :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	boostResponse.Likes, err = service.boostRepo.CountLikes(boost)
+	if err != nil {
+		log.Println("[boostService][GetDetail] error count likes :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(boostResponse, nil, "get detail boost successfully")
+}
+
+func (service *boostService) GetCredit(ctx context.Context) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	credit, err := service.boostRepo.GetDetailCredit(me.Id)
+	if err != nil {
+		log.Println("[boostService][GetCredit] error get detail boost credit :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	creditResponse := responses.BoostCreditResponse{}
+	if credit != nil {
+		creditResponse.Balance = credit.Balance
+	}
+
+	return service.common.StatusOk(creditResponse, nil, "get boost credit successfully")
+}
+
+func (service *boostService) GrantCredit(request *requests.GrantBoostCreditRequest, tx *gorm.DB) responses.Response {
+	user, err := service.userRepo.GetDetailUser(map[string]interface{}{
+		"id":         request.UserId,
+		"deleted_at": nil,
+	}, nil, nil, nil)
+	if err != nil {
+		log.Println("[boostService][GrantCredit] error get detail user :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if user == nil {
+		log.Println("[boostService][GrantCredit] user not found with id", request.UserId)
+		return service.common.StatusNotFound("user not found")
+	}
+
+	err = service.boostRepo.AddCredits(user.Id, request.Credits, tx)
+	if err != nil {
+		log.Println("[boostService][GrantCredit] error add boost credits :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(nil, nil, "grant boost credit successfully")
+}
+
+// retrieveActiveBoosts maps the users with a running boost to the boost, the
+// running boosts are listed by redis and read from the database.
+func retrieveActiveBoosts(redisUtil *utils.Redis, boostRepo repositories.BoostRepositoryInterface) (map[string]*models.BoostModel, error) {
+	members, err := redisUtil.RetrieveSortedSetMembersFromRedis(activeBoostsKey, float64(time.Now().Unix()))
+	if err != nil {
+		return nil, err
+	}
+
+	boosts := map[string]*models.BoostModel{}
+	if len(members) == 0 {
+		return boosts, nil
+	}
+
+	boostIds := []string{}
+	for _, member := range members {
+		if _, boostId, ok := strings.Cut(member, ":"); ok {
+			boostIds = append(boostIds, boostId)
+		}
+	}
+
+	list, err := boostRepo.GetListBoost(boostIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, boost := range list {
+		boosts[boost.UserId] = boost
+	}
+
+	return boosts, nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"dating-app-api/configs"
+	"dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/utils/jwtkeys"
+	"dating-app-api/utils/tokenstore/tokenstoretest"
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	testUserId       = "user-1"
+	testUsername     = "alice"
+	testPassword     = "old-password"
+	testGraceSeconds = 10
+)
+
+// fakeClock only moves when the test advances it
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// fakeUserRepo keeps the users in memory, the methods the tests do not reach
+// are left to the embedded nil interface.
+type fakeUserRepo struct {
+	repositories.UserRepositoryInterface
+	users map[string]*models.UserModel
+}
+
+func (repo *fakeUserRepo) GetDetailUser(whereClause interface{}, whereNotClause interface{}, orClause interface{}, relations []string) (*models.UserModel, error) {
+	where := whereClause.(map[string]interface{})
+	for _, user := range repo.users {
+		if id, ok := where["id"]; ok && id != user.Id {
+			continue
+		}
+		if username, ok := where["username"]; ok && username != user.Username {
+			continue
+		}
+		if _, ok := where["deleted_at"]; ok && user.DeletedAt != nil {
+			continue
+		}
+
+		found := *user
+		return &found, nil
+	}
+
+	return nil, nil
+}
+
+func (repo *fakeUserRepo) UpdateUser(model *models.UserModel, tx *gorm.DB) (*models.UserModel, error) {
+	updated := *model
+	repo.users[model.Id] = &updated
+	return model, nil
+}
+
+func (repo *fakeUserRepo) TouchLastActive(userId string) error {
+	return nil
+}
+
+// testAuth is an auth setup over an in memory token store with one user
+type testAuth struct {
+	t        *testing.T
+	clock    *fakeClock
+	env      *configs.EnviConfig
+	userRepo *fakeUserRepo
+	service  AuthServiceInterface
+	app      *fiber.App
+}
+
+func newTestAuth(t *testing.T) *testAuth {
+	t.Helper()
+
+	hashedPass, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt password: %v", err)
+	}
+
+	clock := &fakeClock{now: time.Now()}
+	env := &configs.EnviConfig{
+		JwtAtExpTime:       15,
+		JwtRtExpTime:       60,
+		JwtKeys:            jwtkeys.NewHMACKeySet("access-secret"),
+		JwtRKeys:           jwtkeys.NewHMACKeySet("refresh-secret"),
+		JwtIssuer:          "dating-app-api",
+		JwtAudience:        "dating-app",
+		JwtRefreshAudience: "dating-app-refresh",
+		JwtRtGraceSeconds:  testGraceSeconds,
+		TokenStore:         tokenstoretest.NewMemoryStore(clock),
+	}
+	userRepo := &fakeUserRepo{users: map[string]*models.UserModel{
+		testUserId: {
+			Id:       testUserId,
+			Username: testUsername,
+			Password: string(hashedPass),
+		},
+	}}
+
+	service := NewAuthService(userRepo, responses.CommondResponse{}, nil, env, middlewares.NewRefreshTokenRotator(env, clock))
+
+	// the token checks of the routes, a request that passes them gets 200
+	app := fiber.New()
+	app.Post("/auth/refresh-token", middlewares.RefreshTokenVerify(env), func(c *fiber.Ctx) error {
+		res := service.RefreshToken(c.Context())
+		return c.Status(res.StatusCode).JSON(res)
+	})
+	app.Get("/users/me", middlewares.UserVerify(env, nil), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	return &testAuth{
+		t:        t,
+		clock:    clock,
+		env:      env,
+		userRepo: userRepo,
+		service:  service,
+		app:      app,
+	}
+}
+
+// login signs in a new device and returns its tokens
+func (a *testAuth) login(password string) responses.AuthResponse {
+	a.t.Helper()
+
+	res := a.service.Login(&requests.AuthRequest{
+		Username:   testUsername,
+		Password:   password,
+		DeviceName: "test device",
+	})
+	if res.StatusCode != fiber.StatusOK {
+		a.t.Fatalf("login status = %d (%s), want 200", res.StatusCode, res.Message)
+	}
+
+	return res.Data.(responses.AuthResponse)
+}
+
+// refresh presents the refresh token and returns the status with the new
+// tokens when it was accepted
+func (a *testAuth) refresh(refreshToken string) (int, responses.AuthResponse) {
+	a.t.Helper()
+
+	status, body := a.request(fiber.MethodPost, "/auth/refresh-token", refreshToken)
+
+	var res struct {
+		Data responses.AuthResponse `json:"data"`
+	}
+	if status == fiber.StatusOK {
+		if err := json.Unmarshal(body, &res); err != nil {
+			a.t.Fatalf("decode refresh response: %v", err)
+		}
+	}
+
+	return status, res.Data
+}
+
+// access presents the access token to a route behind UserVerify, only the
+// rejected statuses are meaningful as the test has no subscription repository
+func (a *testAuth) access(accessToken string) int {
+	a.t.Helper()
+
+	status, _ := a.request(fiber.MethodGet, "/users/me", accessToken)
+	return status
+}
+
+// context returns the request context of the session the access token
+// belongs to, as UserVerify leaves it for the services
+func (a *testAuth) context(accessToken string) context.Context {
+	a.t.Helper()
+
+	claims := &middlewares.TokenClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(accessToken, claims); err != nil {
+		a.t.Fatalf("parse access token: %v", err)
+	}
+
+	return context.WithValue(context.Background(), "metadata", models.TokenMetaData{
+		Id:  claims.Subject,
+		Sid: claims.Sid,
+	})
+}
+
+func (a *testAuth) request(method, path, token string) (int, []byte) {
+	a.t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := a.app.Test(req, -1)
+	if err != nil {
+		a.t.Fatalf("%v %v: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	body := new(json.RawMessage)
+	_ = json.NewDecoder(resp.Body).Decode(body)
+	return resp.StatusCode, *body
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/utils"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+type MatchServiceInterface interface {
+	GetList(ctx context.Context, meta *requests.MetaPaginationRequest) responses.Response
+	Unmatch(ctx context.Context, id string, tx *gorm.DB) responses.Response
+}
+
+type matchService struct {
+	matchRepo repositories.MatchRepositoryInterface
+	swipeRepo repositories.SwipeRepositoryInterface
+	common    responses.CommondResponse
+	redisUtil *utils.Redis
+	envs      *configs.EnviConfig
+}
+
+func NewMatchService(matchRepo repositories.MatchRepositoryInterface, swipeRepo repositories.SwipeRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig) MatchServiceInterface {
+	return &matchService{
+		matchRepo: matchRepo,
+		swipeRepo: swipeRepo,
+		common:    common,
+		redisUtil: redisUtil,
+		envs:      envs,
+	}
+}
+
+func (service *matchService) GetList(ctx context.Context, meta *requests.MetaPaginationRequest) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	matches, count, err := service.matchRepo.GetListMatch(meta, me.Id, []string{"UserOne.Profile", "UserOne.Photos", "UserTwo.Profile", "UserTwo.Photos"})
+	if err != nil {
+		log.Println("[matchService][GetList] error get list match :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	matchResponses := []responses.MatchResponse{}
+	for _, match := range matches {
+		matchResponses = append(matchResponses, responses.MatchResponse{
+			Id:        match.Id,
+			User:      responses.NewUserPublicResponse(match.Partner(me.Id)),
+			CreatedAt: match.CreatedAt,
+		})
+	}
+
+	meta.ParseTotalPage(count)
+	return service.common.StatusOk(matchResponses, meta, "get list match successfully")
+}
+
+func (service *matchService) Unmatch(ctx context.Context, id string, tx *gorm.DB) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	whereClause := map[string]interface{}{
+		"id": id,
+	}
+
+	match, err := service.matchRepo.GetDetailMatch(whereClause, nil)
+	if err != nil {
+		log.Println("[matchService][Unmatch] error get detail match :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if match == nil || (match.UserOneId != me.Id && match.UserTwoId != me.Id) {
+		log.Println("[matchService][Unmatch] match not found with id", id)
+		return service.common.StatusNotFound("match not found")
+	}
+
+	err = service.matchRepo.DeleteMatch(match, tx)
+	if err != nil {
+		log.Println("[matchService][Unmatch] error delete match :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	// the likes that made the match are dropped, one right swipe alone must
+	// not match the pair again
+	err = service.swipeRepo.SavePassPair(match.UserOneId, match.UserTwoId, tx)
+	if err != nil {
+		log.Println("[matchService][Unmatch] error save pass pair :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(nil, nil, "unmatch user successfully")
+}
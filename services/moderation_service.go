@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/repositories"
+	"dating-app-api/utils"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ModerationServiceInterface interface {
+	GetListQueue(meta *requests.MetaPaginationRequest) responses.Response
+	ApprovePhoto(id string, tx *gorm.DB) responses.Response
+	RejectPhoto(id string, request *requests.RejectPhotoRequest, tx *gorm.DB) responses.Response
+}
+
+type moderationService struct {
+	photoRepo        repositories.PhotoRepositoryInterface
+	notificationRepo repositories.NotificationRepositoryInterface
+	common           responses.CommondResponse
+	redisUtil        *utils.Redis
+	envs             *configs.EnviConfig
+}
+
+func NewModerationService(photoRepo repositories.PhotoRepositoryInterface, notificationRepo repositories.NotificationRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig) ModerationServiceInterface {
+	return &moderationService{
+		photoRepo:        photoRepo,
+		notificationRepo: notificationRepo,
+		common:           common,
+		redisUtil:        redisUtil,
+		envs:             envs,
+	}
+}
+
+func (service *moderationService) GetListQueue(meta *requests.MetaPaginationRequest) responses.Response {
+	photos, count, err := service.photoRepo.GetListModerationPhoto(meta)
+	if err != nil {
+		log.Println("[moderationService][GetListQueue] error get list moderation photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	photoResponses := []responses.ModerationPhotoResponse{}
+	for _, photo := range photos {
+		photoResponses = append(photoResponses, responses.ModerationPhotoResponse{
+			PhotoResponse: *responses.NewPhotoResponse(photo),
+			UserId:        photo.UserId,
+		})
+	}
+
+	meta.ParseTotalPage(count)
+	return service.common.StatusOk(photoResponses, meta, "get list moderation queue successfully")
+}
+
+func (service *moderationService) ApprovePhoto(id string, tx *gorm.DB) responses.Response {
+	photo, res := service.retrievePendingPhoto(id)
+	if photo == nil {
+		return res
+	}
+
+	photo.ModerationStatus = models.ModerationApproved
+	moderatedBy := models.ModeratedByAdmin
+	moderatedAt := time.Now().UTC().Format("2006-01-02 15:04:05")
+	photo.ModeratedBy = &moderatedBy
+	photo.ModeratedAt = &moderatedAt
+
+	updated, err := service.photoRepo.ModeratePhoto(photo, tx)
+	if err != nil {
+		log.Println("[moderationService][ApprovePhoto] error moderate photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if !updated {
+		return service.common.StatusBadRequest(nil, "photo already moderated")
+	}
+
+	return service.common.StatusOk(responses.NewPhotoResponse(photo), nil, "approve photo successfully")
+}
+
+func (service *moderationService) RejectPhoto(id string, request *requests.RejectPhotoRequest, tx *gorm.DB) responses.Response {
+	photo, res := service.retrievePendingPhoto(id)
+	if photo == nil {
+		return res
+	}
+
+	photo.ModerationStatus = models.ModerationRejected
+	photo.ModerationReason = &request.Reason
+	moderatedBy := models.ModeratedByAdmin
+	moderatedAt := time.Now().UTC().Format("2006-01-02 15:04:05")
+	photo.ModeratedBy = &moderatedBy
+	photo.ModeratedAt = &moderatedAt
+
+	updated, err := service.photoRepo.ModeratePhoto(photo, tx)
+	if err != nil {
+		log.Println("[moderationService][RejectPhoto] error moderate photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if !updated {
+		return service.common.StatusBadRequest(nil, "photo already moderated")
+	}
+
+	_, err = service.notificationRepo.CreateNotification(photoRejectedNotification(photo), tx)
+	if err != nil {
+		log.Println("[moderationService][RejectPhoto] error create notification :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	// a rejected photo is never shown again, its files are not kept
+	helpers.AfterCommit(tx, func() {
+		deletePhotoFiles(context.Background(), service.envs.Storage, photo)
+	})
+
+	return service.common.StatusOk(responses.NewPhotoResponse(photo), nil, "reject photo successfully")
+}
+
+func (service *moderationService) retrievePendingPhoto(id string) (*models.PhotoModel, responses.Response) {
+	photo, err := service.photoRepo.GetDetailPhoto(map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		log.Println("[moderationService][retrievePendingPhoto] error get detail photo :", err)
+		return nil, service.common.StatusServerError("something went wrong")
+	}
+
+	if photo == nil || photo.Status != models.PhotoReady {
+		log.Println("[moderationService][retrievePendingPhoto] photo not found with id", id)
+		return nil, service.common.StatusNotFound("photo not found")
+	}
+
+	if photo.ModerationStatus != models.ModerationPending {
+		return nil, service.common.StatusBadRequest(nil, "photo already moderated")
+	}
+
+	return photo, responses.Response{}
+}
+
+// photoRejectedNotification tells the owner why the photo is not shown to
+// other users.
+func photoRejectedNotification(photo *models.PhotoModel) *models.NotificationModel {
+	message := "One of your photos was rejected by moderation and is not shown to other users."
+	if photo.ModerationReason != nil {
+		message += " Reason: " + *photo.ModerationReason
+	}
+
+	return &models.NotificationModel{
+		UserId:      photo.UserId,
+		Type:        models.NotificationPhotoRejected,
+		Title:       "Photo rejected",
+		Message:     message,
+		ReferenceId: &photo.Id,
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/utils"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+type NotificationServiceInterface interface {
+	GetList(ctx context.Context, meta *requests.MetaPaginationRequest) responses.Response
+	Read(ctx context.Context, id string, tx *gorm.DB) responses.Response
+}
+
+type notificationService struct {
+	notificationRepo repositories.NotificationRepositoryInterface
+	common           responses.CommondResponse
+	redisUtil        *utils.Redis
+	envs             *configs.EnviConfig
+}
+
+func NewNotificationService(notificationRepo repositories.NotificationRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig) NotificationServiceInterface {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		common:           common,
+		redisUtil:        redisUtil,
+		envs:             envs,
+	}
+}
+
+func (service *notificationService) GetList(ctx context.Context, meta *requests.MetaPaginationRequest) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	notifications, count, err := service.notificationRepo.GetListNotification(meta, me.Id)
+	if err != nil {
+		log.Println("[notificationService][GetList] error get list notification :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	notificationResponses := []responses.NotificationResponse{}
+	for _, notification := range notifications {
+		notificationResponses = append(notificationResponses, *responses.NewNotificationResponse(notification))
+	}
+
+	meta.ParseTotalPage(count)
+	return service.common.StatusOk(notificationResponses, meta, "get list notification successfully")
+}
+
+func (service *notificationService) Read(ctx context.Context, id string, tx *gorm.DB) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	updated, err := service.notificationRepo.ReadNotification(id, me.Id, tx)
+	if err != nil {
+		log.Println("[notificationService][Read] error read notification :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if !updated {
+		log.Println("[notificationService][Read] notification not found with id", id)
+		return service.common.StatusNotFound("notification not found")
+	}
+
+	return service.common.StatusOk(nil, nil, "read notification successfully")
+}
@@ -0,0 +1,482 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/repositories"
+	"dating-app-api/utils"
+	"dating-app-api/utils/imageproc"
+	"dating-app-api/utils/moderation"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const MaxPhotoSize = 5 * 1024 * 1024
+
+// photoContentTypes are the accepted photo content types, sniffed from the
+// file itself instead of trusting the multipart header.
+var photoContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+type PhotoServiceInterface interface {
+	UploadPhoto(ctx context.Context, file *multipart.FileHeader) responses.Response
+	GetListPhoto(ctx context.Context) responses.Response
+	ReorderPhoto(ctx context.Context, request *requests.ReorderPhotoRequest, tx *gorm.DB) responses.Response
+	SetPrimaryPhoto(ctx context.Context, id string, tx *gorm.DB) responses.Response
+	DeletePhoto(ctx context.Context, id string, tx *gorm.DB) responses.Response
+	GetVisiblePhoto(storageKey string) responses.Response
+	FailStalePhotos() error
+	RunStalePhotoWorker(ctx context.Context, interval time.Duration)
+}
+
+type photoService struct {
+	photoRepo        repositories.PhotoRepositoryInterface
+	notificationRepo repositories.NotificationRepositoryInterface
+	common           responses.CommondResponse
+	redisUtil        *utils.Redis
+	envs             *configs.EnviConfig
+	db               *gorm.DB
+}
+
+func NewPhotoService(photoRepo repositories.PhotoRepositoryInterface, notificationRepo repositories.NotificationRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig, db *gorm.DB) PhotoServiceInterface {
+	return &photoService{
+		photoRepo:        photoRepo,
+		notificationRepo: notificationRepo,
+		common:           common,
+		redisUtil:        redisUtil,
+		envs:             envs,
+		db:               db,
+	}
+}
+
+// UploadPhoto stores the photo as processing and queues it on the photo pool,
+// the original file is never written to the storage. The row is created
+// outside the request transaction so the worker can always see it.
+func (service *photoService) UploadPhoto(ctx context.Context, file *multipart.FileHeader) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	if file.Size > MaxPhotoSize {
+		return service.common.StatusBadRequest(nil, fmt.Sprintf("photo size must not exceed %d MB", MaxPhotoSize/1024/1024))
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Println("[photoService][UploadPhoto] error open file :", err)
+		return service.common.StatusBadRequest(nil, "invalid photo")
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		log.Println("[photoService][UploadPhoto] error read file :", err)
+		return service.common.StatusBadRequest(nil, "invalid photo")
+	}
+
+	if !photoContentTypes[http.DetectContentType(data)] {
+		return service.common.StatusBadRequest(nil, "photo must be a jpeg, png or webp image")
+	}
+
+	// the photos are counted under a lock of the user, concurrent uploads can
+	// not both take the last free slot
+	var photo *models.PhotoModel
+	err = service.db.Transaction(func(tx *gorm.DB) error {
+		if err := service.photoRepo.LockUserPhotos(me.Id, tx); err != nil {
+			return err
+		}
+
+		photos, err := service.photoRepo.GetListPhoto(me.Id)
+		if err != nil || len(photos) >= models.MaxPhotos {
+			return err
+		}
+
+		photo, err = service.photoRepo.CreatePhoto(&models.PhotoModel{
+			UserId:           me.Id,
+			StorageKey:       fmt.Sprintf("photos/%s/%s", me.Id, uuid.NewString()),
+			Status:           models.PhotoProcessing,
+			ModerationStatus: models.ModerationPending,
+			Position:         len(photos),
+			IsPrimary:        len(photos) == 0,
+		}, tx)
+		return err
+	})
+	if err != nil {
+		log.Println("[photoService][UploadPhoto] error create photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if photo == nil {
+		return service.common.StatusBadRequest(nil, fmt.Sprintf("maximum %d photos allowed", models.MaxPhotos))
+	}
+
+	queued := service.envs.PhotoPool.Submit(func() {
+		service.processPhoto(photo, data)
+	})
+	if !queued {
+		log.Println("[photoService][UploadPhoto] photo queue is full, drop photo", photo.Id)
+		if err := service.photoRepo.DeletePhoto(photo, service.db); err != nil {
+			log.Println("[photoService][UploadPhoto] error delete photo :", err)
+		}
+		return service.common.StatusTooManyRequest(nil, "too many photos are being processed, please try again later")
+	}
+
+	return service.common.StatusCreated(responses.NewPhotoResponse(photo), "upload photo successfully")
+}
+
+// processPhoto runs on the photo pool, it uploads every variant and marks the
+// photo ready, rejected when the image can not be used or failed when the
+// server could not process it.
+func (service *photoService) processPhoto(photo *models.PhotoModel, data []byte) {
+	ctx := context.Background()
+
+	// a ready photo keeps its files, only the classification was lost
+	ready := false
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("[photoService][processPhoto] panic process photo", photo.Id, ":", r)
+			if ready {
+				return
+			}
+			deletePhotoFiles(ctx, service.envs.Storage, photo)
+			service.finishFailedPhoto(photo, models.PhotoFailed, "failed to process photo")
+		}
+	}()
+
+	result, err := service.envs.ImageProcessor.Process(data)
+	if err != nil {
+		log.Println("[photoService][processPhoto] error process photo", photo.Id, ":", err)
+
+		switch err {
+		case imageproc.ErrUnsupportedImage, imageproc.ErrImageTooSmall, imageproc.ErrImageTooLarge:
+			service.finishFailedPhoto(photo, models.PhotoRejected, err.Error())
+		default:
+			service.finishFailedPhoto(photo, models.PhotoFailed, "failed to process photo")
+		}
+		return
+	}
+
+	processed := &models.PhotoModel{
+		Id:       photo.Id,
+		BlurHash: &result.BlurHash,
+		Width:    &result.Width,
+		Height:   &result.Height,
+		Status:   models.PhotoReady,
+	}
+
+	for _, variant := range imageproc.Variants {
+		body := result.Variants[variant.Name]
+		url, err := service.envs.Storage.Upload(ctx, photo.VariantKey(variant.Name), bytes.NewReader(body), int64(len(body)), "image/jpeg")
+		if err != nil {
+			log.Println("[photoService][processPhoto] error upload photo", photo.Id, ":", err)
+			deletePhotoFiles(ctx, service.envs.Storage, photo)
+			service.finishFailedPhoto(photo, models.PhotoFailed, "failed to store photo")
+			return
+		}
+
+		switch variant.Name {
+		case imageproc.VariantThumbnail:
+			processed.ThumbnailUrl = url
+		case imageproc.VariantMedium:
+			processed.MediumUrl = url
+		case imageproc.VariantFull:
+			processed.Url = url
+		}
+	}
+
+	updated, err := service.photoRepo.FinishPhotoProcessing(processed, service.db)
+	if err != nil {
+		log.Println("[photoService][processPhoto] error update photo :", err)
+		return
+	}
+
+	// the photo was deleted while it was processed
+	if !updated {
+		deletePhotoFiles(ctx, service.envs.Storage, photo)
+		return
+	}
+	ready = true
+
+	service.classifyPhoto(ctx, photo, result)
+}
+
+func (service *photoService) finishFailedPhoto(photo *models.PhotoModel, status string, reason string) {
+	_, err := service.photoRepo.FinishPhotoProcessing(&models.PhotoModel{
+		Id:             photo.Id,
+		Status:         status,
+		RejectedReason: &reason,
+	}, service.db)
+	if err != nil {
+		log.Println("[photoService][processPhoto] error update photo :", err)
+	}
+}
+
+// FailStalePhotos marks the photos processing for longer than the timeout as
+// failed. The original image is only kept in memory, a photo lost by a
+// crashed or restarted worker can not be processed again and has to be
+// uploaded again.
+func (service *photoService) FailStalePhotos() error {
+	photos, err := service.photoRepo.FailStalePhotos(time.Now().Add(-models.PhotoProcessingTimeout), "photo processing was interrupted, please upload it again", service.db)
+	if err != nil {
+		return err
+	}
+
+	// a worker may have stored some variants before it stopped
+	for _, photo := range photos {
+		deletePhotoFiles(context.Background(), service.envs.Storage, photo)
+	}
+
+	if len(photos) > 0 {
+		log.Println("[photoService][FailStalePhotos] failed stale photos :", len(photos))
+	}
+
+	return nil
+}
+
+// RunStalePhotoWorker fails the stale photos every interval until ctx is done.
+func (service *photoService) RunStalePhotoWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := service.FailStalePhotos(); err != nil {
+				log.Println("[photoService][RunStalePhotoWorker] error fail stale photos :", err)
+			}
+		}
+	}
+}
+
+// classifyPhoto lets the classifier approve or reject the photo right away,
+// photos it is not sure about stay pending in the admin moderation queue.
+func (service *photoService) classifyPhoto(ctx context.Context, photo *models.PhotoModel, result *imageproc.Result) {
+	verdict, err := service.envs.PhotoClassifier.Classify(ctx, result.Preview)
+	if err != nil {
+		log.Println("[photoService][classifyPhoto] error classify photo", photo.Id, ":", err)
+		return
+	}
+
+	moderatedBy := models.ModeratedByClassifier
+	moderatedAt := time.Now().UTC().Format("2006-01-02 15:04:05")
+	moderated := &models.PhotoModel{
+		Id:          photo.Id,
+		UserId:      photo.UserId,
+		ModeratedBy: &moderatedBy,
+		ModeratedAt: &moderatedAt,
+	}
+
+	switch verdict.Decision {
+	case moderation.DecisionApprove:
+		moderated.ModerationStatus = models.ModerationApproved
+		_, err = service.photoRepo.ModeratePhoto(moderated, service.db)
+	case moderation.DecisionReject:
+		moderated.ModerationStatus = models.ModerationRejected
+		moderated.ModerationReason = &verdict.Reason
+		rejected := false
+		err = service.db.Transaction(func(tx *gorm.DB) error {
+			updated, err := service.photoRepo.ModeratePhoto(moderated, tx)
+			if err != nil || !updated {
+				return err
+			}
+
+			_, err = service.notificationRepo.CreateNotification(photoRejectedNotification(moderated), tx)
+			rejected = err == nil
+			return err
+		})
+
+		// a rejected photo is never shown again, its files are not kept
+		if rejected {
+			deletePhotoFiles(ctx, service.envs.Storage, photo)
+		}
+	}
+	if err != nil {
+		log.Println("[photoService][classifyPhoto] error moderate photo", photo.Id, ":", err)
+	}
+}
+
+// deletePhotoFiles removes every variant of the photo from the storage, a
+// leftover file is only logged.
+func deletePhotoFiles(ctx context.Context, storage utils.Storage, photo *models.PhotoModel) {
+	for _, variant := range imageproc.Variants {
+		if err := storage.Delete(ctx, photo.VariantKey(variant.Name)); err != nil {
+			log.Println("[photoService][deletePhotoFiles] error delete photo from storage :", err)
+		}
+	}
+}
+
+func (service *photoService) GetListPhoto(ctx context.Context) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	photos, err := service.photoRepo.GetListPhoto(me.Id)
+	if err != nil {
+		log.Println("[photoService][GetListPhoto] error get list photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	photoResponses := []responses.PhotoResponse{}
+	for _, photo := range photos {
+		photoResponses = append(photoResponses, *responses.NewPhotoResponse(photo))
+	}
+
+	return service.common.StatusOk(photoResponses, nil, "get list photo successfully")
+}
+
+func (service *photoService) ReorderPhoto(ctx context.Context, request *requests.ReorderPhotoRequest, tx *gorm.DB) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	photos, err := service.photoRepo.GetListPhoto(me.Id)
+	if err != nil {
+		log.Println("[photoService][ReorderPhoto] error get list photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	// the new order has to name every photo of the user exactly once
+	owned := map[string]bool{}
+	for _, photo := range photos {
+		owned[photo.Id] = true
+	}
+
+	if len(request.PhotoIds) != len(photos) {
+		return service.common.StatusBadRequest(nil, "photo_ids must contain all of your photos")
+	}
+
+	for _, photoId := range request.PhotoIds {
+		if !owned[photoId] {
+			return service.common.StatusBadRequest(nil, "photo_ids must contain all of your photos")
+		}
+		delete(owned, photoId)
+	}
+
+	err = service.photoRepo.UpdatePhotoPositions(me.Id, request.PhotoIds, tx)
+	if err != nil {
+		log.Println("[photoService][ReorderPhoto] error update photo positions :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	photos, err = service.photoRepo.GetListPhoto(me.Id)
+	if err != nil {
+		log.Println("[photoService][ReorderPhoto] error get list photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	photoResponses := []responses.PhotoResponse{}
+	for _, photo := range photos {
+		photoResponses = append(photoResponses, *responses.NewPhotoResponse(photo))
+	}
+
+	return service.common.StatusOk(photoResponses, nil, "reorder photo successfully")
+}
+
+func (service *photoService) SetPrimaryPhoto(ctx context.Context, id string, tx *gorm.DB) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	photo, err := service.photoRepo.GetDetailPhoto(map[string]interface{}{
+		"id":      id,
+		"user_id": me.Id,
+	})
+	if err != nil {
+		log.Println("[photoService][SetPrimaryPhoto] error get detail photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if photo == nil {
+		log.Println("[photoService][SetPrimaryPhoto] photo not found with id", id)
+		return service.common.StatusNotFound("photo not found")
+	}
+
+	err = service.photoRepo.SetPrimaryPhoto(me.Id, photo.Id, tx)
+	if err != nil {
+		log.Println("[photoService][SetPrimaryPhoto] error set primary photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	photo.IsPrimary = true
+	return service.common.StatusOk(responses.NewPhotoResponse(photo), nil, "set primary photo successfully")
+}
+
+func (service *photoService) DeletePhoto(ctx context.Context, id string, tx *gorm.DB) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	photos, err := service.photoRepo.GetListPhoto(me.Id)
+	if err != nil {
+		log.Println("[photoService][DeletePhoto] error get list photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	var photo *models.PhotoModel
+	remaining := []string{}
+	for _, p := range photos {
+		if p.Id == id {
+			photo = p
+			continue
+		}
+		remaining = append(remaining, p.Id)
+	}
+
+	if photo == nil {
+		log.Println("[photoService][DeletePhoto] photo not found with id", id)
+		return service.common.StatusNotFound("photo not found")
+	}
+
+	err = service.photoRepo.DeletePhoto(photo, tx)
+	if err != nil {
+		log.Println("[photoService][DeletePhoto] error delete photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	// close the gap in positions and hand the primary flag to the next photo
+	err = service.photoRepo.UpdatePhotoPositions(me.Id, remaining, tx)
+	if err != nil {
+		log.Println("[photoService][DeletePhoto] error update photo positions :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if photo.IsPrimary && len(remaining) > 0 {
+		err = service.photoRepo.SetPrimaryPhoto(me.Id, remaining[0], tx)
+		if err != nil {
+			log.Println("[photoService][DeletePhoto] error set primary photo :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+	}
+
+	// the files go once the row is gone, a leftover file is only logged
+	helpers.AfterCommit(tx, func() {
+		deletePhotoFiles(context.Background(), service.envs.Storage, photo)
+	})
+
+	return service.common.StatusOk(nil, nil, "delete photo successfully")
+}
+
+// GetVisiblePhoto looks up the photo stored under storageKey, photos that are
+// still processed, rejected or not approved yet are not found.
+func (service *photoService) GetVisiblePhoto(storageKey string) responses.Response {
+	photo, err := service.photoRepo.GetDetailPhoto(map[string]interface{}{
+		"storage_key": storageKey,
+	})
+	if err != nil {
+		log.Println("[photoService][GetVisiblePhoto] error get detail photo :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if photo == nil || !photo.IsVisible() {
+		return service.common.StatusNotFound("photo not found")
+	}
+
+	return service.common.StatusOk(responses.NewPhotoResponse(photo), nil, "get photo successfully")
+}
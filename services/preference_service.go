@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/repositories"
+	"dating-app-api/utils"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+type PreferenceServiceInterface interface {
+	GetPreference(ctx context.Context, tx *gorm.DB) responses.Response
+	UpdatePreference(ctx context.Context, request *requests.PreferenceRequest, tx *gorm.DB) responses.Response
+}
+
+type preferenceService struct {
+	preferenceRepo repositories.PreferenceRepositoryInterface
+	userRepo       repositories.UserRepositoryInterface
+	common         responses.CommondResponse
+	redisUtil      *utils.Redis
+	envs           *configs.EnviConfig
+}
+
+func NewPreferenceService(preferenceRepo repositories.PreferenceRepositoryInterface, userRepo repositories.UserRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig) PreferenceServiceInterface {
+	return &preferenceService{
+		preferenceRepo: preferenceRepo,
+		userRepo:       userRepo,
+		common:         common,
+		redisUtil:      redisUtil,
+		envs:           envs,
+	}
+}
+
+// GetPreference stores the defaults derived from the profile on the first
+// read, so the user is matched with what they were shown.
+func (service *preferenceService) GetPreference(ctx context.Context, tx *gorm.DB) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	preference, err := service.preferenceRepo.GetDetailPreference(meta.Id)
+	if err != nil {
+		log.Println("[preferenceService][GetPreference] error get detail preference :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if preference != nil {
+		return service.common.StatusOk(responses.NewPreferenceResponse(preference), nil, "get preference successfully")
+	}
+
+	profile, err := service.userRepo.GetDetailProfile(meta.Id)
+	if err != nil {
+		log.Println("[preferenceService][GetPreference] error get detail profile :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if profile == nil {
+		log.Println("[preferenceService][GetPreference] profile not found with user id", meta.Id)
+		return service.common.StatusNotFound("profile not found")
+	}
+
+	preference, err = service.preferenceRepo.CreatePreference(models.DefaultPreference(profile), tx)
+	if err != nil {
+		log.Println("[preferenceService][GetPreference] error create preference :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(responses.NewPreferenceResponse(preference), nil, "get preference successfully")
+}
+
+func (service *preferenceService) UpdatePreference(ctx context.Context, request *requests.PreferenceRequest, tx *gorm.DB) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	profile, err := service.userRepo.GetDetailProfile(meta.Id)
+	if err != nil {
+		log.Println("[preferenceService][UpdatePreference] error get detail profile :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if profile == nil {
+		log.Println("[preferenceService][UpdatePreference] profile not found with user id", meta.Id)
+		return service.common.StatusNotFound("profile not found")
+	}
+
+	preference := &models.PreferenceModel{
+		UserId:        meta.Id,
+		MinAge:        request.MinAge,
+		MaxAge:        request.MaxAge,
+		Genders:       request.Genders,
+		MaxDistance:   optionalInt(request.MaxDistance),
+		MinHeight:     optionalInt(request.MinHeight),
+		MaxHeight:     optionalInt(request.MaxHeight),
+		RequirePhotos: request.RequirePhotos,
+	}
+
+	preference, err = service.preferenceRepo.UpsertPreference(preference, tx)
+	if err != nil {
+		log.Println("[preferenceService][UpdatePreference] error upsert preference :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(responses.NewPreferenceResponse(preference), nil, "update preference successfully")
+}
+
+// optionalInt maps the 0 of an unset request number to null.
+func optionalInt(value int) *int {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"dating-app-api/configs"
+	middlewares "dating-app-api/deliveries/middlewares"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
+	"dating-app-api/repositories"
+	"dating-app-api/utils"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SubscriptionServiceInterface interface {
+	GetSubscription(ctx context.Context) responses.Response
+	GetListPlan() responses.Response
+	ExpireSubscriptions() error
+	RunExpiryWorker(ctx context.Context, interval time.Duration)
+}
+
+type subscriptionService struct {
+	subscriptionRepo repositories.SubscriptionRepositoryInterface
+	common           responses.CommondResponse
+	redisUtil        *utils.Redis
+	envs             *configs.EnviConfig
+	db               *gorm.DB
+}
+
+func NewSubscriptionService(subscriptionRepo repositories.SubscriptionRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig, db *gorm.DB) SubscriptionServiceInterface {
+	return &subscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		common:           common,
+		redisUtil:        redisUtil,
+		envs:             envs,
+		db:               db,
+	}
+}
+
+func (service *subscriptionService) GetSubscription(ctx context.Context) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	subscription, err := service.subscriptionRepo.GetActiveSubscription(meta.Id)
+	if err != nil {
+		log.Println("[subscriptionService][GetSubscription] error get active subscription :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if subscription == nil {
+		return service.common.StatusOk(responses.SubscriptionResponse{Active: false}, nil, "get subscription successfully")
+	}
+
+	var planResponse responses.PlanResponse
+	err = helpers.Unmarshal(subscription.Plan, &planResponse)
+	if err != nil {
+		log.Println("[subscriptionService][GetSubscription] error unmarshal plan model to responses :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	subscriptionResponse := responses.SubscriptionResponse{
+		Active:  true,
+		Plan:    &planResponse,
+		StartAt: subscription.StartAt,
+		EndAt:   subscription.EndAt,
+	}
+
+	return service.common.StatusOk(subscriptionResponse, nil, "get subscription successfully")
+}
+
+func (service *subscriptionService) GetListPlan() responses.Response {
+	plans, err := service.subscriptionRepo.GetListPlan()
+	if err != nil {
+		log.Println("[subscriptionService][GetListPlan] error get list plan :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	planResponses := []responses.PlanResponse{}
+	err = helpers.Unmarshal(plans, &planResponses)
+	if err != nil {
+		log.Println("[subscriptionService][GetListPlan] error unmarshal plan model to responses :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(planResponses, nil, "get list plan successfully")
+}
+
+// ExpireSubscriptions downgrades users whose subscription has ended and drops
+// their cached status so the next request sees them as free users.
+func (service *subscriptionService) ExpireSubscriptions() error {
+	var userIds []string
+	err := service.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		userIds, err = service.subscriptionRepo.ExpireSubscriptions(tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, userId := range userIds {
+		err = service.redisUtil.DeleteDataFromRedis(middlewares.SubscriptionStatusKey(userId))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(userIds) > 0 {
+		log.Println("[subscriptionService][ExpireSubscriptions] expired subscriptions for", len(userIds), "users")
+	}
+
+	return nil
+}
+
+// RunExpiryWorker expires the ended subscriptions every interval until ctx is
+// done.
+func (service *subscriptionService) RunExpiryWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := service.ExpireSubscriptions(); err != nil {
+				log.Println("[subscriptionService][RunExpiryWorker] error expire subscriptions :", err)
+			}
+		}
+	}
+}
@@ -3,10 +3,20 @@ package services
 import (
 	"context"
 	"dating-app-api/configs"
+	"dating-app-api/entities/models"
 	"dating-app-api/entities/requests"
 	"dating-app-api/entities/responses"
+	"dating-app-api/helpers"
 	"dating-app-api/repositories"
 	"dating-app-api/utils"
+	"dating-app-api/utils/rating"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
 /*
@@ -16,13 +26,644 @@ import (
     to query NOT IN
 */
 type SwipeServiceInterface interface {
-	SwipService(ctx context.Context, req *requests.SwipeRequest) responses.Response
+	SwipService(ctx context.Context, req *requests.SwipeRequest, tx *gorm.DB) responses.Response
+	GetQuota(ctx context.Context) responses.Response
+	GetListReceivedLikes(ctx context.Context, meta *requests.MetaPaginationRequest) responses.Response
+	Rewind(ctx context.Context, tx *gorm.DB) responses.Response
 }
 
 type swipeService struct {
-	swipeRepo repositories.SwipeRepositoryInterface
-	userRepo  repositories.UserRepositoryInterface
-	common    responses.CommondResponse
-	redisUtil *utils.Redis
-	envs      *configs.EnviConfig
+	swipeRepo        repositories.SwipeRepositoryInterface
+	userRepo         repositories.UserRepositoryInterface
+	matchRepo        repositories.MatchRepositoryInterface
+	notificationRepo repositories.NotificationRepositoryInterface
+	ratings          *rating.Updater
+	common           responses.CommondResponse
+	redisUtil        *utils.Redis
+	envs             *configs.EnviConfig
+}
+
+func NewSwipeService(swipeRepo repositories.SwipeRepositoryInterface, userRepo repositories.UserRepositoryInterface, matchRepo repositories.MatchRepositoryInterface, notificationRepo repositories.NotificationRepositoryInterface, ratings *rating.Updater, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig) SwipeServiceInterface {
+	return &swipeService{
+		swipeRepo:        swipeRepo,
+		userRepo:         userRepo,
+		matchRepo:        matchRepo,
+		notificationRepo: notificationRepo,
+		ratings:          ratings,
+		common:           common,
+		redisUtil:        redisUtil,
+		envs:             envs,
+	}
+}
+
+func (service *swipeService) SwipService(ctx context.Context, req *requests.SwipeRequest, tx *gorm.DB) (res responses.Response) {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	if req.UserId == meta.Id {
+		log.Println("[swipeService][SwipService] user swipe on himself")
+		return service.common.StatusBadRequest(nil, "cannot swipe yourself")
+	}
+
+	quota, reserved, err := service.reserveSwipe(ctx)
+	if err != nil {
+		log.Println("[swipeService][SwipService] error reserve swipe :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if !reserved {
+		log.Println("[swipeService][SwipService] maximum swipe in a day")
+		return service.common.StatusTooManyRequest(quota, "maximum swipe in a day")
+	}
+
+	// the swipe is given back when it is not stored
+	defer func() {
+		if res.StatusCode == http.StatusCreated {
+			return
+		}
+		if err := service.redisUtil.DecrementDataInRedis(swipeAttemptKey(meta.Id, time.Now())); err != nil {
+			log.Println("[swipeService][SwipService] error release swipe :", err)
+		}
+	}()
+
+	whereClause := map[string]interface{}{
+		"id":         req.UserId,
+		"deleted_at": nil,
+	}
+
+	target, err := service.userRepo.GetDetailUser(whereClause, nil, nil, []string{"Profile", "Photos"})
+	if err != nil {
+		log.Println("[swipeService][SwipService] error get detail user :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if target == nil {
+		log.Println("[swipeService][SwipService] user not found with id", req.UserId)
+		return service.common.StatusNotFound("user not found")
+	}
+
+	// the pair is locked first, a like of the target committed while this
+	// swipe runs would otherwise be missed and the match never created
+	userOneId, userTwoId := models.MatchPair(meta.Id, target.Id)
+	err = service.matchRepo.LockPair(userOneId, userTwoId, tx)
+	if err != nil {
+		log.Println("[swipeService][SwipService] error lock pair :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	match, err := service.matchRepo.GetDetailMatch(map[string]interface{}{
+		"user_one_id": userOneId,
+		"user_two_id": userTwoId,
+	}, nil)
+	if err != nil {
+		log.Println("[swipeService][SwipService] error get detail match :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if match != nil {
+		log.Println("[swipeService][SwipService] user already matched with", target.Id)
+		return service.common.StatusBadRequest(nil, "user already matched")
+	}
+
+	if req.Type == models.SwipeSuper {
+		superQuota, reserved, err := service.reserveSuperLike(ctx)
+		if err != nil {
+			log.Println("[swipeService][SwipService] error reserve super like :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+
+		if !reserved {
+			log.Println("[swipeService][SwipService] maximum super like in a day")
+			return service.common.StatusTooManyRequest(superQuota, "maximum super like in a day")
+		}
+		quota.SuperLikes = superQuota
+
+		// the super like is given back when the swipe is not stored
+		defer func() {
+			if res.StatusCode == http.StatusCreated {
+				return
+			}
+			if err := service.redisUtil.DecrementDataInRedis(superLikeKey(meta.Id, time.Now())); err != nil {
+				log.Println("[swipeService][SwipService] error release super like :", err)
+			}
+		}()
+	}
+
+	// the swipe the user made before on the target, the pair lock keeps it
+	// from changing until the new one is saved
+	previous, err := service.swipeRepo.FindSwipe(meta.Id, target.Id, tx)
+	if err != nil {
+		log.Println("[swipeService][SwipService] error find swipe :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	var swipe *models.SwipeModel
+	switch req.Type {
+	case models.SwipeRight:
+		swipe, err = service.swipeRepo.SaveLikes(meta.Id, target.Id, tx)
+	case models.SwipeSuper:
+		swipe, err = service.swipeRepo.SaveSuperLikes(meta.Id, target.Id, tx)
+	default:
+		swipe, err = service.swipeRepo.SavePass(meta.Id, target.Id, tx)
+	}
+	if err != nil {
+		log.Println("[swipeService][SwipService] error save swipe :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if swipe.IsSuper {
+		_, err = service.notificationRepo.CreateNotification(superLikeNotification(swipe), tx)
+		if err != nil {
+			log.Println("[swipeService][SwipService] error create notification :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+	}
+
+	if err := service.userRepo.TouchLastActive(meta.Id); err != nil {
+		log.Println("[swipeService][SwipService] error touch last active :", err)
+	}
+
+	// the rating of the target is updated in the background once the swipe is
+	// committed, a repeated swipe in the same direction is no new outcome. An
+	// outcome dropped on a full queue is corrected by the next recompute.
+	if previous == nil || previous.Direction != swipe.Direction {
+		outcome := rating.Outcome{
+			SwiperId: meta.Id,
+			TargetId: target.Id,
+			Right:    swipe.Direction == models.SwipeRight,
+		}
+		helpers.AfterCommit(tx, func() {
+			if !service.ratings.Submit(outcome) {
+				log.Println("[swipeService][SwipService] rating queue is full")
+			}
+		})
+	}
+
+	// the target leaves the feed once the swipe is committed, a missed write
+	// only shows the target again
+	seenDuration := helpers.GetTimeToMidnight() + time.Duration(service.envs.SwipeDedupeDays-1)*24*time.Hour
+	helpers.AfterCommit(tx, func() {
+		err := service.redisUtil.AddSetMemberToRedis(swipedKey(meta.Id, time.Now()), target.Id, seenDuration)
+		if err != nil {
+			log.Println("[swipeService][SwipService] error save swiped user to redis :", err)
+		}
+	})
+
+	swipeResponse := responses.SwipeResponse{
+		Id:        swipe.Id,
+		UserId:    swipe.TargetId,
+		Type:      swipeType(swipe),
+		CreatedAt: swipe.CreatedAt,
+		Quota:     quota,
+	}
+
+	if swipe.Direction != models.SwipeRight {
+		return service.common.StatusCreated(swipeResponse, "swipe user successfully")
+	}
+
+	// a right swipe back on someone who already liked us is a match
+	like, err := service.swipeRepo.FindLikes(target.Id, meta.Id, tx)
+	if err != nil {
+		log.Println("[swipeService][SwipService] error find likes :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if like == nil {
+		return service.common.StatusCreated(swipeResponse, "swipe user successfully")
+	}
+
+	match, err = service.matchRepo.CreateMatch(&models.MatchModel{
+		UserOneId: userOneId,
+		UserTwoId: userTwoId,
+		SwipeId:   &swipe.Id,
+	}, tx)
+	if err != nil {
+		log.Println("[swipeService][SwipService] error create match :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	swipeResponse.Matched = true
+	swipeResponse.MatchId = match.Id
+	swipeResponse.User = responses.NewUserPublicResponse(target)
+
+	return service.common.StatusCreated(swipeResponse, "swipe user successfully")
+}
+
+func (service *swipeService) GetQuota(ctx context.Context) responses.Response {
+	quota, err := service.retrieveSwipeQuota(ctx)
+	if err != nil {
+		log.Println("[swipeService][GetQuota] error get swipe quota :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	quota.SuperLikes, err = service.retrieveSuperLikeQuota(ctx)
+	if err != nil {
+		log.Println("[swipeService][GetQuota] error get super like quota :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(quota, nil, "get swipe quota successfully")
+}
+
+// Rewind undoes the most recent swipe of the user when it is recent enough. The
+// swiped user shows up in discovery again and a match created by the swipe is
+// reverted, the rating of the swiped user is corrected by the next recompute.
+func (service *swipeService) Rewind(ctx context.Context, tx *gorm.DB) (res responses.Response) {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	quota, reserved, err := service.reserveRewind(ctx)
+	if err != nil {
+		log.Println("[swipeService][Rewind] error reserve rewind :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if !reserved {
+		log.Println("[swipeService][Rewind] maximum rewind in a day")
+		return service.common.StatusTooManyRequest(quota, "maximum rewind in a day")
+	}
+
+	// the rewind is given back when nothing was rewound
+	defer func() {
+		if res.StatusCode == http.StatusOK {
+			return
+		}
+		if err := service.redisUtil.DecrementDataInRedis(rewindAttemptKey(meta.Id, time.Now())); err != nil {
+			log.Println("[swipeService][Rewind] error release rewind :", err)
+		}
+	}()
+
+	swipe, err := service.swipeRepo.GetLastSwipe(meta.Id, tx)
+	if err != nil {
+		log.Println("[swipeService][Rewind] error get last swipe :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if swipe == nil {
+		log.Println("[swipeService][Rewind] no swipe to rewind for user", meta.Id)
+		return service.common.StatusNotFound("no swipe to rewind")
+	}
+
+	swipedAt, err := helpers.ParseDateTime(swipe.CreatedAt)
+	if err != nil {
+		log.Println("[swipeService][Rewind] error parse swipe created at :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if time.Since(swipedAt) > time.Duration(service.envs.SwipeRewindMinutes)*time.Minute {
+		log.Println("[swipeService][Rewind] last swipe is too old to rewind", swipe.Id)
+		return service.common.StatusBadRequest(nil, "last swipe can no longer be rewound")
+	}
+
+	// only the first swipe of the pair is rewound by deleting it, the swipe it
+	// replaced is not kept so a replacing swipe can not be rewound
+	if swipe.PreviousDirection != nil {
+		log.Println("[swipeService][Rewind] last swipe replaced an earlier swipe", swipe.Id)
+		return service.common.StatusBadRequest(nil, "last swipe replaced an earlier swipe and can not be rewound")
+	}
+
+	// a match of the pair is only reverted when this swipe created it, a swipe
+	// the other user already matched on can not be rewound
+	userOneId, userTwoId := models.MatchPair(meta.Id, swipe.TargetId)
+	err = service.matchRepo.LockPair(userOneId, userTwoId, tx)
+	if err != nil {
+		log.Println("[swipeService][Rewind] error lock pair :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	match, err := service.matchRepo.GetDetailMatch(map[string]interface{}{
+		"user_one_id": userOneId,
+		"user_two_id": userTwoId,
+	}, nil)
+	if err != nil {
+		log.Println("[swipeService][Rewind] error get detail match :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	unmatched := false
+	if match != nil {
+		if match.SwipeId == nil || *match.SwipeId != swipe.Id {
+			log.Println("[swipeService][Rewind] last swipe belongs to a match", match.Id)
+			return service.common.StatusBadRequest(nil, "last swipe already led to a match, unmatch instead")
+		}
+
+		err = service.matchRepo.DeleteMatch(match, tx)
+		if err != nil {
+			log.Println("[swipeService][Rewind] error delete match :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+		unmatched = true
+	}
+
+	err = service.swipeRepo.DeleteSwipe(swipe, tx)
+	if err != nil {
+		log.Println("[swipeService][Rewind] error delete swipe :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if swipe.IsSuper {
+		err = service.notificationRepo.DeleteNotificationByReference(models.NotificationSuperLike, swipe.Id, tx)
+		if err != nil {
+			log.Println("[swipeService][Rewind] error delete notification :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+	}
+
+	rewindResponse := responses.RewindResponse{
+		Id:        swipe.Id,
+		UserId:    swipe.TargetId,
+		Type:      swipeType(swipe),
+		Unmatched: unmatched,
+		Quota:     quota,
+	}
+
+	target, err := service.userRepo.GetDetailUser(map[string]interface{}{
+		"id":         swipe.TargetId,
+		"deleted_at": nil,
+	}, nil, nil, []string{"Profile", "Photos"})
+	if err != nil {
+		log.Println("[swipeService][Rewind] error get detail user :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+	rewindResponse.User = responses.NewUserPublicResponse(target)
+
+	// the swiped user was added to the seen set of the day of the swipe and a
+	// super like is given back to the balance of that day
+	helpers.AfterCommit(tx, func() {
+		err := service.redisUtil.RemoveSetMemberFromRedis(swipedKey(meta.Id, swipedAt.Local()), swipe.TargetId)
+		if err != nil {
+			log.Println("[swipeService][Rewind] error remove swiped user from redis :", err)
+		}
+
+		if swipe.IsSuper {
+			err = service.redisUtil.DecrementDataInRedis(superLikeKey(meta.Id, swipedAt.Local()))
+			if err != nil {
+				log.Println("[swipeService][Rewind] error release super like :", err)
+			}
+		}
+	})
+
+	return service.common.StatusOk(rewindResponse, nil, "rewind swipe successfully")
+}
+
+// GetListReceivedLikes lists who liked the user and is still waiting for a
+// swipe back. Without the see likes entitlement only the count and
+// placeholders are returned, revealed users can be swiped right from the list.
+func (service *swipeService) GetListReceivedLikes(ctx context.Context, meta *requests.MetaPaginationRequest) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+	subscription, _ := ctx.Value("subscription").(models.SubscriptionStatus)
+
+	likes, count, err := service.swipeRepo.GetListReceivedLikes(meta, me.Id, []string{"Swiper.Profile", "Swiper.Photos"})
+	if err != nil {
+		log.Println("[swipeService][GetListReceivedLikes] error get list received likes :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	revealed := subscription.HasEntitlement(models.EntitlementSeeLikes)
+
+	likeResponses := []*responses.LikeResponse{}
+	for _, like := range likes {
+		likeResponses = append(likeResponses, responses.NewLikeResponse(like, revealed))
+	}
+
+	meta.ParseTotalPage(count)
+	return service.common.StatusOk(likeResponses, meta, "get list received likes successfully")
+}
+
+// retrieveSwipeQuota reads how many swipes the user made today.
+func (service *swipeService) retrieveSwipeQuota(ctx context.Context) (*responses.QuotaResponse, error) {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	var used int
+	err := service.redisUtil.RetrieveDataFromRedis(swipeAttemptKey(meta.Id, time.Now()), &used)
+	if err != nil && err.Error() != redis.Nil.Error() {
+		return nil, err
+	}
+
+	return service.swipeQuota(ctx, used), nil
+}
+
+// reserveSwipe counts one swipe of today, it returns false with the untouched
+// quota when the daily limit is reached. The counter is incremented before
+// the limit is checked so concurrent swipes can not both take the last one.
+func (service *swipeService) reserveSwipe(ctx context.Context) (*responses.QuotaResponse, bool, error) {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	key := swipeAttemptKey(meta.Id, time.Now())
+	used, err := service.redisUtil.IncrementDataInRedisWithExpiry(key, helpers.GetTimeToMidnight())
+	if err != nil {
+		return nil, false, err
+	}
+
+	quota := service.swipeQuota(ctx, int(used))
+	if quota.Limit == nil || int(used) <= *quota.Limit {
+		return quota, true, nil
+	}
+
+	if err := service.redisUtil.DecrementDataInRedis(key); err != nil {
+		return nil, false, err
+	}
+
+	return service.swipeQuota(ctx, int(used)-1), false, nil
+}
+
+// swipeQuota builds the swipe quota from the swipes made today, users with
+// the unlimited swipes entitlement get their quota without limit and remaining.
+func (service *swipeService) swipeQuota(ctx context.Context, used int) *responses.QuotaResponse {
+	subscription, _ := ctx.Value("subscription").(models.SubscriptionStatus)
+
+	quota := &responses.QuotaResponse{
+		Unlimited: subscription.HasEntitlement(models.EntitlementUnlimitedSwipes),
+		Used:      used,
+		ResetsAt:  nextReset().Format(time.RFC3339),
+	}
+
+	if !quota.Unlimited {
+		limit := service.envs.SwipeDailyLimit
+		remaining := max(limit-used, 0)
+		quota.Limit = &limit
+		quota.Remaining = &remaining
+	}
+
+	return quota
+}
+
+// reserveRewind counts one rewind of today, it returns false with the
+// untouched quota when the daily limit is reached.
+func (service *swipeService) reserveRewind(ctx context.Context) (*responses.QuotaResponse, bool, error) {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	key := rewindAttemptKey(meta.Id, time.Now())
+	used, err := service.redisUtil.IncrementDataInRedisWithExpiry(key, helpers.GetTimeToMidnight())
+	if err != nil {
+		return nil, false, err
+	}
+
+	quota := service.rewindQuota(ctx, int(used))
+	if quota.Limit == nil || int(used) <= *quota.Limit {
+		return quota, true, nil
+	}
+
+	if err := service.redisUtil.DecrementDataInRedis(key); err != nil {
+		return nil, false, err
+	}
+
+	return service.rewindQuota(ctx, int(used)-1), false, nil
+}
+
+// rewindQuota builds the rewind quota from the rewinds made today, users with
+// the rewind entitlement get their quota without limit and remaining.
+func (service *swipeService) rewindQuota(ctx context.Context, used int) *responses.QuotaResponse {
+	subscription, _ := ctx.Value("subscription").(models.SubscriptionStatus)
+
+	quota := &responses.QuotaResponse{
+		Unlimited: subscription.HasEntitlement(models.EntitlementRewind),
+		Used:      used,
+		ResetsAt:  nextReset().Format(time.RFC3339),
+	}
+
+	if !quota.Unlimited {
+		limit := service.envs.SwipeRewindDailyLimit
+		remaining := max(limit-used, 0)
+		quota.Limit = &limit
+		quota.Remaining = &remaining
+	}
+
+	return quota
+}
+
+// retrieveSuperLikeQuota reads the super like balance of today, users with an
+// active subscription get the premium limit.
+func (service *swipeService) retrieveSuperLikeQuota(ctx context.Context) (*responses.QuotaResponse, error) {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	used, err := service.reconcileSuperLikes(meta.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.superLikeQuota(ctx, used), nil
+}
+
+// reserveSuperLike takes one super like from the balance of today, it returns
+// false with the untouched quota when the balance is used up.
+func (service *swipeService) reserveSuperLike(ctx context.Context) (*responses.QuotaResponse, bool, error) {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	if _, err := service.reconcileSuperLikes(meta.Id); err != nil {
+		return nil, false, err
+	}
+
+	key := superLikeKey(meta.Id, time.Now())
+	used, err := service.redisUtil.IncrementDataInRedis(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	quota := service.superLikeQuota(ctx, int(used))
+	if *quota.Remaining >= 0 {
+		return quota, true, nil
+	}
+
+	if err := service.redisUtil.DecrementDataInRedis(key); err != nil {
+		return nil, false, err
+	}
+
+	quota = service.superLikeQuota(ctx, int(used)-1)
+	return quota, false, nil
+}
+
+// reconcileSuperLikes returns how many super likes the user made today. The
+// balance lives in redis, when the key is missing (first super like of the
+// day or redis lost it) it is rebuilt from the swipe history.
+func (service *swipeService) reconcileSuperLikes(userId string) (int, error) {
+	key := superLikeKey(userId, time.Now())
+
+	var used int
+	err := service.redisUtil.RetrieveDataFromRedis(key, &used)
+	if err == nil {
+		return used, nil
+	}
+
+	if err.Error() != redis.Nil.Error() {
+		return 0, err
+	}
+
+	count, err := service.swipeRepo.CountSuperLikes(userId, nextReset().AddDate(0, 0, -1))
+	if err != nil {
+		return 0, err
+	}
+
+	// a concurrent request may have rebuilt the balance first, its value wins
+	_, err = service.redisUtil.SaveDataToRedisIfNotExists(key, count, helpers.GetTimeToMidnight())
+	if err != nil {
+		return 0, err
+	}
+
+	err = service.redisUtil.RetrieveDataFromRedis(key, &used)
+	if err != nil {
+		return 0, err
+	}
+
+	return used, nil
+}
+
+func (service *swipeService) superLikeQuota(ctx context.Context, used int) *responses.QuotaResponse {
+	subscription, _ := ctx.Value("subscription").(models.SubscriptionStatus)
+
+	limit := service.envs.SuperLikeDailyLimit
+	if subscription.Active {
+		limit = service.envs.SuperLikePremiumDailyLimit
+	}
+	remaining := limit - used
+
+	return &responses.QuotaResponse{
+		Limit:     &limit,
+		Used:      used,
+		Remaining: &remaining,
+		ResetsAt:  nextReset().Format(time.RFC3339),
+	}
+}
+
+// superLikeNotification tells the target someone super liked them, the
+// swiper is shown on top of their feed.
+func superLikeNotification(swipe *models.SwipeModel) *models.NotificationModel {
+	return &models.NotificationModel{
+		UserId:      swipe.TargetId,
+		Type:        models.NotificationSuperLike,
+		Title:       "New super like",
+		Message:     "Someone super liked you, they are waiting on top of your feed.",
+		ReferenceId: &swipe.Id,
+	}
+}
+
+// swipeType is the type of the swipe request that stored the swipe.
+func swipeType(swipe *models.SwipeModel) string {
+	if swipe.IsSuper {
+		return models.SwipeSuper
+	}
+	return swipe.Direction
+}
+
+// swipeAttemptKey holds how many swipes userId made on the given day
+func swipeAttemptKey(userId string, day time.Time) string {
+	return fmt.Sprintf("swipes:%v:%v", userId, day.Format("2006-01-02"))
+}
+
+// rewindAttemptKey holds how many rewinds userId made on the given day
+func rewindAttemptKey(userId string, day time.Time) string {
+	return fmt.Sprintf("rewinds:%v:%v", userId, day.Format("2006-01-02"))
+}
+
+// superLikeKey holds how many super likes userId made on the given day
+func superLikeKey(userId string, day time.Time) string {
+	return fmt.Sprintf("superlike:%v:%v", userId, day.Format("2006-01-02"))
+}
+
+// swipedKey is the redis set of users swiped by userId on the given day
+func swipedKey(userId string, day time.Time) string {
+	return fmt.Sprintf("swiped:%v:%v", userId, day.Format("2006-01-02"))
+}
+
+// nextReset is when the daily quotas start over
+func nextReset() time.Time {
+	return time.Now().Add(helpers.GetTimeToMidnight())
 }
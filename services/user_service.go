@@ -10,9 +10,20 @@ import (
 	"dating-app-api/helpers"
 	"dating-app-api/repositories"
 	"dating-app-api/utils"
+	"dating-app-api/utils/ranking"
+	"dating-app-api/utils/rating"
+	"dating-app-api/utils/tokenstore"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
 	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -22,26 +33,38 @@ type UserServiceInterface interface {
 	RegisterUser(request *requests.CreateUserRequest, tx *gorm.DB) responses.Response
 	UpdateUser(request *requests.UpdateUserRequest, id string, tx *gorm.DB) responses.Response
 	GetDetail(id string) responses.Response
-	GetList(meta *requests.MetaPaginationRequest) responses.Response
+	GetList(ctx context.Context, meta *requests.MetaPaginationRequest, request *requests.DiscoverRequest) responses.Response
 	DeleteUser(id string, tx *gorm.DB) responses.Response
 	CheckUsername(username string) responses.Response
 	ChangePassword(ctx context.Context, password string, tx *gorm.DB) responses.Response
 	VerifyUser(request *requests.VerifyUser, tx *gorm.DB) responses.Response
+	CreatePayment(ctx context.Context, request *requests.CreatePaymentRequest, tx *gorm.DB) responses.Response
+	GetProfile(ctx context.Context) responses.Response
+	UpdateProfile(ctx context.Context, request *requests.ProfileRequest, tx *gorm.DB) responses.Response
+	UpdateLocation(ctx context.Context, request *requests.LocationRequest, tx *gorm.DB) responses.Response
 }
 
 type userService struct {
-	userRepo  repositories.UserRepositoryInterface
-	common    responses.CommondResponse
-	redisUtil *utils.Redis
-	envs      *configs.EnviConfig
+	userRepo         repositories.UserRepositoryInterface
+	paymentRepo      repositories.PaymentRepositoryInterface
+	subscriptionRepo repositories.SubscriptionRepositoryInterface
+	preferenceRepo   repositories.PreferenceRepositoryInterface
+	boostRepo        repositories.BoostRepositoryInterface
+	common           responses.CommondResponse
+	redisUtil        *utils.Redis
+	envs             *configs.EnviConfig
 }
 
-func NewUserService(userRepo repositories.UserRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig) UserServiceInterface {
+func NewUserService(userRepo repositories.UserRepositoryInterface, paymentRepo repositories.PaymentRepositoryInterface, subscriptionRepo repositories.SubscriptionRepositoryInterface, preferenceRepo repositories.PreferenceRepositoryInterface, boostRepo repositories.BoostRepositoryInterface, common responses.CommondResponse, redisUtil *utils.Redis, envs *configs.EnviConfig) UserServiceInterface {
 	return &userService{
-		userRepo:  userRepo,
-		common:    common,
-		redisUtil: redisUtil,
-		envs:      envs,
+		userRepo:         userRepo,
+		paymentRepo:      paymentRepo,
+		subscriptionRepo: subscriptionRepo,
+		preferenceRepo:   preferenceRepo,
+		boostRepo:        boostRepo,
+		common:           common,
+		redisUtil:        redisUtil,
+		envs:             envs,
 	}
 }
 
@@ -53,7 +76,7 @@ func (service *userService) RegisterUser(request *requests.CreateUserRequest, tx
 	if err != nil {
 		if err.Error() == redis.Nil.Error() {
 			count.Count = 1
-			err = service.redisUtil.SaveDataToRedis(request.Username+"-attempt", count, time.Duration(helpers.GetTimeToMidnight())*time.Hour)
+			err = service.redisUtil.SaveDataToRedis(request.Username+"-attempt", count, helpers.GetTimeToMidnight())
 			if err != nil {
 				log.Println("[userService][RegisterUser] error save attemps to redis :", err)
 				return service.common.StatusServerError("something went wrong")
@@ -68,7 +91,7 @@ func (service *userService) RegisterUser(request *requests.CreateUserRequest, tx
 			return service.common.StatusBadRequest(nil, "maximum register attempts in day")
 		} else {
 			count.Count += 1
-			err = service.redisUtil.SaveDataToRedis(request.Username+"-attempt", count, time.Duration(helpers.GetTimeToMidnight())*time.Hour)
+			err = service.redisUtil.SaveDataToRedis(request.Username+"-attempt", count, helpers.GetTimeToMidnight())
 			if err != nil {
 				log.Println("[userService][RegisterUser] error save attemps to redis :", err)
 				return service.common.StatusServerError("something went wrong")
@@ -113,10 +136,22 @@ func (service *userService) RegisterUser(request *requests.CreateUserRequest, tx
 		return service.common.StatusServerError("something went wrong")
 	}
 
+	session := models.SessionModel{
+		UserId:     user.Id,
+		DeviceName: request.DeviceName,
+		Ip:         request.Ip,
+		UserAgent:  request.UserAgent,
+	}
+	if err := middlewares.CreateSession(service.envs, &session); err != nil {
+		log.Println("[userService][RegisterUser] error create session :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
 	meta := models.TokenMetaData{
 		Id:     user.Id,
+		Sid:    session.Id,
 		Verify: false,
-		RtId:   "rt",
+		RtId:   uuid.NewString(),
 	}
 	token, err := middlewares.GenerateToken(service.envs, meta, false)
 	if err != nil {
@@ -152,7 +187,36 @@ func (service *userService) UpdateUser(request *requests.UpdateUserRequest, id s
 		return service.common.StatusNotFound("user not found")
 	}
 
-	return service.common.StatusOk(nil, nil, "update user successfully")
+	if request.Username != user.Username {
+		existing, err := service.userRepo.GetDetailUser(map[string]interface{}{"username": request.Username}, nil, nil, nil)
+		if err != nil {
+			log.Println("[userService][UpdateUser] error get detail user :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+
+		if existing != nil {
+			log.Println("[userService][UpdateUser] username already exist")
+			return service.common.StatusBadRequest(nil, "username already exist")
+		}
+	}
+
+	user.Username = request.Username
+	user.PhoneNumber = request.PhoneNumber
+
+	user, err = service.userRepo.UpdateUser(user, tx)
+	if err != nil {
+		log.Println("[userService][UpdateUser] error update user :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	var userResponse responses.UserResponse
+	err = helpers.Unmarshal(user, &userResponse)
+	if err != nil {
+		log.Println("[userService][UpdateUser] error unmarshal user model to responses :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(userResponse, nil, "update user successfully")
 }
 
 func (service *userService) GetDetail(id string) responses.Response {
@@ -202,29 +266,745 @@ func (service *userService) CheckUsername(username string) responses.Response {
 	return service.common.StatusOk(nil, nil, "username valid")
 }
 
-func (service *userService) GetList(meta *requests.MetaPaginationRequest) responses.Response {
+func (service *userService) GetList(ctx context.Context, meta *requests.MetaPaginationRequest, request *requests.DiscoverRequest) responses.Response {
+	me := ctx.Value("metadata").(models.TokenMetaData)
+
+	// users swiped within the dedupe window are kept in one redis set per day
+	swipedKeys := []string{}
+	for i := 0; i < service.envs.SwipeDedupeDays; i++ {
+		swipedKeys = append(swipedKeys, swipedKey(me.Id, time.Now().AddDate(0, 0, -i)))
+	}
+
+	excludeIds, err := service.redisUtil.RetrieveSetMembersFromRedis(swipedKeys...)
+	if err != nil {
+		log.Println("[userService][GetList] error get swiped users from redis :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+	excludeIds = append(excludeIds, me.Id)
+
+	// only users who filled their dating profile can be discovered
+	filter := &requests.DiscoverFilter{
+		ExcludeIds:      excludeIds,
+		OnlyWithProfile: true,
+		MaxDistanceKm:   request.MaxDistance,
+	}
+
+	location, err := service.userRepo.GetDetailLocation(me.Id)
+	if err != nil {
+		log.Println("[userService][GetList] error get detail location :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if location != nil {
+		filter.Origin = &requests.Coordinate{
+			Latitude:  location.Latitude,
+			Longitude: location.Longitude,
+		}
+	} else if filter.MaxDistanceKm > 0 {
+		return service.common.StatusBadRequest(nil, "set your location before filtering by distance")
+	}
+
+	profile, err := service.userRepo.GetDetailProfile(me.Id)
+	if err != nil {
+		log.Println("[userService][GetList] error get detail profile :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	// the own profile is needed to match the preferences of the candidates
+	if profile == nil {
+		return service.common.StatusBadRequest(nil, "complete your profile before discovering users")
+	}
+
+	preference, err := service.preferenceRepo.GetDetailPreference(me.Id)
+	if err != nil {
+		log.Println("[userService][GetList] error get detail preference :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if preference == nil {
+		preference = models.DefaultPreference(profile)
+	}
+
+	filter.Preference = &requests.DiscoverPreference{
+		MinAge:        preference.MinAge,
+		MaxAge:        preference.MaxAge,
+		Genders:       preference.Genders,
+		MinHeight:     preference.MinHeight,
+		MaxHeight:     preference.MaxHeight,
+		RequirePhotos: preference.RequirePhotos,
+	}
+	filter.Viewer = &requests.DiscoverViewer{
+		Id:     me.Id,
+		Age:    profile.Age(),
+		Gender: profile.Gender,
+		Height: profile.Height,
+	}
+
+	// the max_distance query param narrows the preference for this request
+	if filter.Origin != nil && filter.MaxDistanceKm == 0 && preference.MaxDistance != nil {
+		filter.MaxDistanceKm = *preference.MaxDistance
+	}
+
+	boosts, err := retrieveActiveBoosts(service.redisUtil, service.boostRepo)
+	if err != nil {
+		log.Println("[userService][GetList] error get active boosts from redis :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	for userId := range boosts {
+		filter.BoostedIds = append(filter.BoostedIds, userId)
+	}
+
+	// the feed is made of pools of RankPoolSize candidates by recent activity,
+	// every pool is ranked on its own and the requested page is cut from the
+	// rankings of the pools it spans. The total counts every candidate.
+	poolSize := service.envs.RankPoolSize
+	firstPool := meta.Offset / poolSize * poolSize
+
+	ranked := []*models.UserModel{}
+	var count int64
+	for offset := firstPool; offset < meta.Offset+meta.Limit; offset += poolSize {
+		pool := &requests.MetaPaginationRequest{
+			Limit:  poolSize,
+			Offset: offset,
+			SortBy: "last_active_at",
+			Order:  "DESC",
+		}
+
+		users, total, err := service.userRepo.GetListUser(pool, filter, []string{"Profile", "Photos"})
+		if err != nil {
+			log.Println("[userService][GetList] error get list user :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+		count = total
+
+		ranked = append(ranked, service.rankUsers(profile, users, boosts)...)
+
+		if len(users) < poolSize {
+			break
+		}
+	}
+
+	userResponses := []*responses.UserPublicResponse{}
+	impressions := []*models.BoostImpressionModel{}
+	for i := meta.Offset - firstPool; i < len(ranked) && i < meta.Offset-firstPool+meta.Limit; i++ {
+		userResponses = append(userResponses, responses.NewUserPublicResponse(ranked[i]))
+
+		if boost, ok := boosts[ranked[i].Id]; ok {
+			impressions = append(impressions, &models.BoostImpressionModel{
+				BoostId:  boost.Id,
+				ViewerId: me.Id,
+			})
+		}
+	}
+
+	// the feed is served even when the boost stats can not be recorded
+	if len(impressions) > 0 {
+		if err := service.boostRepo.CreateImpressions(impressions); err != nil {
+			log.Println("[userService][GetList] error create boost impressions :", err)
+		}
+	}
+
+	meta.ParseTotalPage(count)
+	return service.common.StatusOk(userResponses, meta, "get list user successfully")
+}
+
+// rankUsers orders the discovery candidates with the configured ranker. The
+// seed changes per viewer and day, so the pages of a feed stay in the same
+// order while browsing but every user sees a different order of equals.
+// Super likes stay on top whatever their score and boosted users follow, the
+// multiplier of their boost orders them among themselves.
+func (service *userService) rankUsers(viewer *models.ProfileModel, users []*models.UserModel, boosts map[string]*models.BoostModel) []*models.UserModel {
+	now := time.Now().UTC()
+
+	h := fnv.New64a()
+	h.Write([]byte(viewer.UserId + now.Format("2006-01-02")))
+	seed := service.envs.RankSeed ^ int64(h.Sum64())
+
+	byId := map[string]*models.UserModel{}
+	candidates := []ranking.Candidate{}
+	for _, user := range users {
+		byId[user.Id] = user
+
+		candidate := ranking.Candidate{
+			UserId:       user.Id,
+			DistanceKm:   user.Distance,
+			Completeness: profileCompleteness(user),
+		}
+
+		if lastActiveAt, err := helpers.ParseDateTime(user.LastActiveAt); err == nil {
+			candidate.LastActiveAt = lastActiveAt
+		}
+
+		candidate.Desirability = rating.Normalize(rating.Initial)
+		if user.Rating != nil {
+			candidate.Desirability = rating.Normalize(*user.Rating)
+		}
+
+		if user.Profile != nil {
+			candidate.Interests = user.Profile.Interests
+		}
+
+		if boost, ok := boosts[user.Id]; ok {
+			candidate.Boost = boost.Multiplier
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	scores := service.envs.Ranker.Rank(ranking.Viewer{
+		UserId:    viewer.UserId,
+		Interests: viewer.Interests,
+	}, candidates, now, seed)
+
+	ranked := []*models.UserModel{}
+	for _, score := range scores {
+		ranked = append(ranked, byId[score.UserId])
+	}
+
+	slices.SortStableFunc(ranked, func(a, b *models.UserModel) int {
+		if a.SuperLiked != b.SuperLiked {
+			return boolOrder(a.SuperLiked)
+		}
+		_, aBoosted := boosts[a.Id]
+		_, bBoosted := boosts[b.Id]
+		if aBoosted != bBoosted {
+			return boolOrder(aBoosted)
+		}
+		return 0
+	})
+
+	return ranked
+}
+
+// boolOrder sorts the true side first.
+func boolOrder(first bool) int {
+	if first {
+		return -1
+	}
+	return 1
+}
+
+// profileCompleteness is the share of the optional profile parts a user
+// filled, photos count in full from 3 visible photos.
+func profileCompleteness(user *models.UserModel) float64 {
+	if user.Profile == nil {
+		return 0
+	}
+
+	filled := 0.0
+	for _, ok := range []bool{
+		user.Profile.Bio != nil && *user.Profile.Bio != "",
+		user.Profile.Job != nil && *user.Profile.Job != "",
+		user.Profile.Education != nil && *user.Profile.Education != "",
+		user.Profile.Height != nil,
+		user.Profile.Location != nil && *user.Profile.Location != "",
+		len(user.Profile.Interests) > 0,
+	} {
+		if ok {
+			filled++
+		}
+	}
+
+	photos := 0.0
+	for _, photo := range user.Photos {
+		if photo.IsVisible() {
+			photos++
+		}
+	}
+	filled += min(photos/3, 1)
+
+	return filled / 7
+}
+
+func (service *userService) GetProfile(ctx context.Context) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	profile, err := service.userRepo.GetDetailProfile(meta.Id)
+	if err != nil {
+		log.Println("[userService][GetProfile] error get detail profile :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
 
-	// users, count, err := service.userRepo.GetListUser(meta,)
+	if profile == nil {
+		log.Println("[userService][GetProfile] profile not found with user id", meta.Id)
+		return service.common.StatusNotFound("profile not found")
+	}
 
-	return service.common.StatusOk(nil, nil, "get list user successfully")
+	return service.common.StatusOk(responses.NewProfileResponse(profile), nil, "get profile successfully")
+}
+
+func (service *userService) UpdateProfile(ctx context.Context, request *requests.ProfileRequest, tx *gorm.DB) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	var profile *models.ProfileModel
+	err := helpers.Unmarshal(request, &profile)
+	if err != nil {
+		log.Println("[userService][UpdateProfile] error unmarshal profile request to model :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+	profile.UserId = meta.Id
+
+	// interests are compared between users, so they are kept lowercase and
+	// without duplicates
+	interests := []string{}
+	for _, interest := range request.Interests {
+		interest = strings.ToLower(strings.TrimSpace(interest))
+		if !slices.Contains(interests, interest) {
+			interests = append(interests, interest)
+		}
+	}
+	profile.Interests = interests
+
+	profile, err = service.userRepo.UpsertProfile(profile, tx)
+	if err != nil {
+		log.Println("[userService][UpdateProfile] error upsert profile :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	// a new profile starts with preferences derived from it, so it can be
+	// matched against other users right away
+	_, err = service.preferenceRepo.CreatePreference(models.DefaultPreference(profile), tx)
+	if err != nil {
+		log.Println("[userService][UpdateProfile] error create preference :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(responses.NewProfileResponse(profile), nil, "update profile successfully")
 }
 
 func (service *userService) DeleteUser(id string, tx *gorm.DB) responses.Response {
+	user, err := service.userRepo.GetDetailUser(map[string]interface{}{
+		"id":         id,
+		"deleted_at": nil,
+	}, nil, nil, nil)
+	if err != nil {
+		log.Println("[userService][DeleteUser] error get detail user :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if user == nil {
+		log.Println("[userService][DeleteUser] user not found with id", id)
+		return service.common.StatusNotFound("user not found")
+	}
+
+	deletedAt := time.Now().UTC().Format("2006-01-02 15:04:05")
+	user.DeletedAt = &deletedAt
+	_, err = service.userRepo.UpdateUser(user, tx)
+	if err != nil {
+		log.Println("[userService][DeleteUser] error update user :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	err = service.envs.TokenStore.Revoke(user.Id, tokenstore.AllSessions)
+	if err != nil {
+		log.Println("[userService][DeleteUser] error revoke sessions :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
 
 	return service.common.StatusOk(nil, nil, "delete user successfully")
 }
 
+func (service *userService) CreatePayment(ctx context.Context, request *requests.CreatePaymentRequest, tx *gorm.DB) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	whereClause := map[string]interface{}{
+		"id": meta.Id,
+	}
+
+	user, err := service.userRepo.GetDetailUser(whereClause, nil, nil, nil)
+	if err != nil {
+		log.Println("[userService][CreatePayment] error get detail user :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if user == nil {
+		log.Println("[userService][CreatePayment] user not found with id", meta.Id)
+		return service.common.StatusNotFound("user not found")
+	}
+
+	plan, err := service.subscriptionRepo.GetDetailPlan(map[string]interface{}{
+		"code": request.PlanCode,
+	})
+	if err != nil {
+		log.Println("[userService][CreatePayment] error get detail plan :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if plan == nil {
+		log.Println("[userService][CreatePayment] plan not found with code", request.PlanCode)
+		return service.common.StatusNotFound("plan not found")
+	}
+
+	payment, err := service.paymentRepo.GetDetailPayment(map[string]interface{}{
+		"user_id": user.Id,
+		"status":  models.PaymentPending,
+	})
+	if err != nil {
+		log.Println("[userService][CreatePayment] error get detail payment :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	// reuse the virtual account until it expires so the user always pays the same number
+	if payment != nil && (payment.IsExpired() || payment.PlanId == nil || *payment.PlanId != plan.Id) {
+		payment.Status = models.PaymentExpired
+		_, err = service.paymentRepo.UpdatePayment(payment, tx)
+		if err != nil {
+			log.Println("[userService][CreatePayment] error update expired payment :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+		payment = nil
+	}
+
+	if payment == nil {
+		vaNumber, err := helpers.GenerateNumericCode(16 - len(service.envs.PaymentVaPrefix))
+		if err != nil {
+			log.Println("[userService][CreatePayment] error generate va number :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+
+		payment, err = service.paymentRepo.CreatePayment(&models.PaymentModel{
+			UserId:    user.Id,
+			PlanId:    &plan.Id,
+			VaNumber:  service.envs.PaymentVaPrefix + vaNumber,
+			Amount:    plan.Price,
+			Status:    models.PaymentPending,
+			ExpiredAt: time.Now().UTC().Add(time.Duration(service.envs.PaymentExpiryMinutes) * time.Minute).Format("2006-01-02 15:04:05"),
+		}, tx)
+		if err != nil {
+			log.Println("[userService][CreatePayment] error insert payment :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+	}
+
+	var paymentResponse responses.PaymentResponse
+	err = helpers.Unmarshal(payment, &paymentResponse)
+	if err != nil {
+		log.Println("[userService][CreatePayment] error unmarshal payment model to responses :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusCreated(paymentResponse, "create payment successfully")
+}
+
 func (service *userService) VerifyUser(request *requests.VerifyUser, tx *gorm.DB) responses.Response {
 
+	// gateways retry callbacks, a known transaction id always gets the original result
+	var original responses.Response
+	err := service.redisUtil.RetrieveDataFromRedis(paymentCallbackKey(request.TransactionId), &original)
+	if err == nil {
+		log.Println("[userService][VerifyUser] replay callback from redis with transaction id", request.TransactionId)
+		return original
+	}
+
+	transaction := &models.PaymentTransactionModel{
+		TransactionId: request.TransactionId,
+		VaNumber:      request.VANumber,
+		Amount:        request.Amount,
+	}
+
+	created, err := service.paymentRepo.CreateTransaction(transaction, tx)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error insert payment transaction :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if !created {
+		return service.replayPaymentCallback(request.TransactionId)
+	}
+
+	res := service.applyPayment(request, transaction, tx)
+	if res.StatusCode >= http.StatusInternalServerError {
+		return res
+	}
+
+	// the stored result is what retried callbacks get, it is kept in redis
+	// once the transaction is committed
+	result := paymentCallbackResult(res)
+	response := helpers.JsonMinify(result)
+	transaction.Response = &response
+	_, err = service.paymentRepo.UpdateTransaction(transaction, tx)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error update payment transaction :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	helpers.AfterCommit(tx, func() {
+		err := service.redisUtil.SaveDataToRedis(paymentCallbackKey(transaction.TransactionId), result, 24*time.Hour)
+		if err != nil {
+			log.Println("[userService][VerifyUser] error save payment transaction to redis :", err)
+		}
+	})
+
+	return res
+}
+
+// paymentCallbackResult strips the tokens from the result of a callback, a
+// replayed callback must not hand out a session of the user again.
+func paymentCallbackResult(res responses.Response) responses.Response {
+	if auth, ok := res.Data.(responses.AuthResponse); ok {
+		res.Data = auth.User
+	}
+	return res
+}
+
+func (service *userService) replayPaymentCallback(transactionId string) responses.Response {
+	transaction, err := service.paymentRepo.GetDetailTransaction(map[string]interface{}{
+		"transaction_id": transactionId,
+	})
+	if err != nil {
+		log.Println("[userService][VerifyUser] error get detail payment transaction :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if transaction == nil || transaction.Response == nil {
+		log.Println("[userService][VerifyUser] payment transaction still processed", transactionId)
+		return service.common.StatusServerError("payment still processed")
+	}
+
+	var original responses.Response
+	err = json.Unmarshal([]byte(*transaction.Response), &original)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error unmarshal payment transaction response :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	err = service.redisUtil.SaveDataToRedis(paymentCallbackKey(transactionId), original, 24*time.Hour)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error save payment transaction to redis :", err)
+	}
+
+	log.Println("[userService][VerifyUser] replay callback from db with transaction id", transactionId)
+	return original
+}
+
+// applyPayment matches the callback against a pending virtual account, every
+// status change is appended to payment events. Only an exact amount activates
+// the user, partial and over payments are recorded and left for manual review.
+func (service *userService) applyPayment(request *requests.VerifyUser, transaction *models.PaymentTransactionModel, tx *gorm.DB) responses.Response {
+
+	payment, err := service.paymentRepo.GetDetailPayment(map[string]interface{}{
+		"va_number": request.VANumber,
+		"status":    models.PaymentPending,
+	})
+	if err != nil {
+		log.Println("[userService][VerifyUser] error get detail payment :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if payment == nil {
+		log.Println("[userService][VerifyUser] payment not found with va number", request.VANumber)
+		err = service.recordPaymentEvent(transaction, nil, models.PaymentRejected, "virtual account not found", tx)
+		if err != nil {
+			log.Println("[userService][VerifyUser] error insert payment event :", err)
+			return service.common.StatusServerError("something went wrong")
+		}
+		return service.common.StatusNotFound("virtual account not found")
+	}
+	transaction.PaymentId = &payment.Id
+
+	toStatus := models.PaymentPaid
+	expectedAmount := math.Round(payment.Amount * 100)
+	paidAmount := math.Round(request.Amount * 100)
+	switch {
+	case payment.IsExpired():
+		toStatus = models.PaymentExpired
+	case paidAmount < expectedAmount:
+		toStatus = models.PaymentPartialPaid
+	case paidAmount > expectedAmount:
+		toStatus = models.PaymentOverPaid
+	}
+
+	err = service.recordPaymentEvent(transaction, payment, toStatus, "", tx)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error insert payment event :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	payment.Status = toStatus
+	if toStatus != models.PaymentExpired {
+		paidAt := time.Now().UTC().Format("2006-01-02 15:04:05")
+		payment.PaidAt = &paidAt
+	}
+
+	_, err = service.paymentRepo.UpdatePayment(payment, tx)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error update payment :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	var paymentResponse responses.PaymentResponse
+	err = helpers.Unmarshal(payment, &paymentResponse)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error unmarshal payment model to responses :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	switch toStatus {
+	case models.PaymentExpired:
+		log.Println("[userService][VerifyUser] virtual account expired", request.VANumber)
+		return service.common.StatusBadRequest(paymentResponse, "virtual account expired")
+	case models.PaymentPartialPaid, models.PaymentOverPaid:
+		log.Println("[userService][VerifyUser] amount not match for va number", request.VANumber)
+		return service.common.StatusOk(paymentResponse, nil, "payment recorded, amount not match")
+	}
+
+	user, err := service.userRepo.GetDetailUser(map[string]interface{}{"id": payment.UserId}, nil, nil, nil)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error get detail user :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	if user == nil {
+		log.Println("[userService][VerifyUser] user not found with id", payment.UserId)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	err = service.activateSubscription(payment, tx)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error activate subscription :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	user.Verified = true
+	_, err = service.userRepo.UpdateUser(user, tx)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error update user :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	var userResponse responses.UserResponse
+	err = helpers.Unmarshal(user, &userResponse)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error unmarshal user model to responses :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	session := models.SessionModel{
+		UserId:     user.Id,
+		DeviceName: request.DeviceName,
+		Ip:         request.Ip,
+		UserAgent:  request.UserAgent,
+	}
+	if err := middlewares.CreateSession(service.envs, &session); err != nil {
+		log.Println("[userService][VerifyUser] error create session :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	meta := models.TokenMetaData{
+		Id:     user.Id,
+		Sid:    session.Id,
+		Verify: true,
+		RtId:   uuid.NewString(),
+	}
+
+	token, err := middlewares.GenerateToken(service.envs, meta, false)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error generate token :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	rToken, err := middlewares.GenerateToken(service.envs, meta, true)
+	if err != nil {
+		log.Println("[userService][VerifyUser] error generate token :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
 	authResponse := responses.AuthResponse{
-		// User:         &userResponse,
-		// AccessToken:  token,
-		// RefreshToken: rToken,
+		User:         &userResponse,
+		AccessToken:  token,
+		RefreshToken: rToken,
 	}
 
 	return service.common.StatusCreated(authResponse, "verify user successfully")
 }
 
+// activateSubscription starts the paid plan, a renewal is stacked after the
+// subscription that is still running so no paid day is lost.
+func (service *userService) activateSubscription(payment *models.PaymentModel, tx *gorm.DB) error {
+	if payment.PlanId == nil {
+		return errors.New("payment has no plan")
+	}
+
+	plan, err := service.subscriptionRepo.GetDetailPlan(map[string]interface{}{
+		"id": *payment.PlanId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if plan == nil {
+		return errors.New("plan not found")
+	}
+
+	current, err := service.subscriptionRepo.GetLastSubscription(payment.UserId)
+	if err != nil {
+		return err
+	}
+
+	startAt := time.Now().UTC()
+	if current != nil {
+		currentEndAt, err := helpers.ParseDateTime(current.EndAt)
+		if err != nil {
+			return err
+		}
+		startAt = currentEndAt
+	}
+
+	_, err = service.subscriptionRepo.CreateSubscription(&models.SubscriptionModel{
+		UserId:    payment.UserId,
+		PlanId:    plan.Id,
+		PaymentId: &payment.Id,
+		Status:    models.SubscriptionActive,
+		StartAt:   startAt.Format("2006-01-02 15:04:05"),
+		EndAt:     startAt.AddDate(0, 0, plan.DurationDays).Format("2006-01-02 15:04:05"),
+	}, tx)
+	if err != nil {
+		return err
+	}
+
+	if plan.BoostCredits > 0 {
+		err = service.boostRepo.AddCredits(payment.UserId, plan.BoostCredits, tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the cached status is dropped once the subscription is visible, a request
+	// in between would cache the old status again
+	helpers.AfterCommit(tx, func() {
+		err := service.redisUtil.DeleteDataFromRedis(middlewares.SubscriptionStatusKey(payment.UserId))
+		if err != nil {
+			log.Println("[userService][VerifyUser] error delete subscription status from redis :", err)
+		}
+	})
+
+	return nil
+}
+
+func (service *userService) recordPaymentEvent(transaction *models.PaymentTransactionModel, payment *models.PaymentModel, toStatus string, note string, tx *gorm.DB) error {
+	event := &models.PaymentEventModel{
+		TransactionId: transaction.TransactionId,
+		ToStatus:      toStatus,
+		Amount:        transaction.Amount,
+	}
+
+	if payment != nil {
+		event.PaymentId = &payment.Id
+		event.FromStatus = &payment.Status
+	}
+
+	if note != "" {
+		event.Note = &note
+	}
+
+	return service.paymentRepo.CreateEvent(event, tx)
+}
+
+func paymentCallbackKey(transactionId string) string {
+	return "payment-callback:" + transactionId
+}
+
 func (service *userService) ChangePassword(ctx context.Context, password string, tx *gorm.DB) responses.Response {
 
 	meta := ctx.Value("metadata").(models.TokenMetaData)
@@ -259,5 +1039,29 @@ func (service *userService) ChangePassword(ctx context.Context, password string,
 		return service.common.StatusServerError("something went wrong")
 	}
 
-	return service.common.StatusOk(nil, nil, "change password user successfully")
+	// tokens issued with the old password stop working on every device
+	err = service.envs.TokenStore.Revoke(userModel.Id, tokenstore.AllSessions)
+	if err != nil {
+		log.Println("[userService][ChangePassword] error revoke sessions :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(nil, nil, "change password user successfully, please login again")
+}
+
+func (service *userService) UpdateLocation(ctx context.Context, request *requests.LocationRequest, tx *gorm.DB) responses.Response {
+	meta := ctx.Value("metadata").(models.TokenMetaData)
+
+	// only the coarse position is kept, see models.LocationGrid
+	_, err := service.userRepo.UpsertLocation(&models.UserLocationModel{
+		UserId:    meta.Id,
+		Latitude:  models.CoarsenCoordinate(*request.Latitude),
+		Longitude: models.CoarsenCoordinate(*request.Longitude),
+	}, tx)
+	if err != nil {
+		log.Println("[userService][UpdateLocation] error upsert location :", err)
+		return service.common.StatusServerError("something went wrong")
+	}
+
+	return service.common.StatusOk(nil, nil, "update location successfully")
 }
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"dating-app-api/configs"
+	"dating-app-api/entities/models"
+	"dating-app-api/entities/requests"
+	"dating-app-api/entities/responses"
+	"dating-app-api/utils/ranking"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newTestUserService(auth *testAuth) UserServiceInterface {
+	return NewUserService(auth.userRepo, nil, nil, nil, nil, responses.CommondResponse{}, nil, auth.env)
+}
+
+// assertSignedOut checks that none of the tokens is accepted anymore
+func assertSignedOut(t *testing.T, auth *testAuth, devices ...responses.AuthResponse) {
+	t.Helper()
+
+	for i, device := range devices {
+		if status := auth.access(device.AccessToken); status != http.StatusUnauthorized {
+			t.Errorf("device %d access status = %d, want 401", i, status)
+		}
+		if status, _ := auth.refresh(device.RefreshToken); status != http.StatusUnauthorized {
+			t.Errorf("device %d refresh status = %d, want 401", i, status)
+		}
+	}
+
+	ids, err := auth.env.TokenStore.RetrieveSessionIds(testUserId)
+	if err != nil {
+		t.Fatalf("retrieve session ids: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("sessions left = %v, want none", ids)
+	}
+}
+
+func TestChangePasswordRevokesAllSessions(t *testing.T) {
+	auth := newTestAuth(t)
+	device := auth.login(testPassword)
+	other := auth.login(testPassword)
+
+	res := newTestUserService(auth).ChangePassword(auth.context(device.AccessToken), "new-password", nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("change password status = %d (%s), want 200", res.StatusCode, res.Message)
+	}
+
+	assertSignedOut(t, auth, device, other)
+
+	if res := auth.service.Login(&requests.AuthRequest{Username: testUsername, Password: testPassword}); res.StatusCode != http.StatusBadRequest {
+		t.Errorf("login with the old password status = %d, want 400", res.StatusCode)
+	}
+	auth.login("new-password")
+}
+
+func TestDeleteUserRevokesAllSessions(t *testing.T) {
+	auth := newTestAuth(t)
+	device := auth.login(testPassword)
+	other := auth.login(testPassword)
+
+	res := newTestUserService(auth).DeleteUser(testUserId, nil)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("delete user status = %d (%s), want 200", res.StatusCode, res.Message)
+	}
+
+	assertSignedOut(t, auth, device, other)
+}
+
+func TestRankUsersBoostedFirst(t *testing.T) {
+	service := &userService{envs: &configs.EnviConfig{
+		Ranker: ranking.NewDefaultRanker(ranking.Weights{Recency: 0.5, Desirability: 0.5}, false),
+	}}
+	viewer := &models.ProfileModel{UserId: "viewer"}
+
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	old := time.Now().UTC().Add(-30 * 24 * time.Hour).Format("2006-01-02 15:04:05")
+	high, low := 1400.0, 600.0
+	newUsers := func() []*models.UserModel {
+		return []*models.UserModel{
+			{Id: "high", LastActiveAt: now, Rating: &high},
+			{Id: "boost-small", LastActiveAt: old, Rating: &low},
+			{Id: "boost-large", LastActiveAt: old, Rating: &low},
+			{Id: "super", LastActiveAt: old, Rating: &low, SuperLiked: true},
+		}
+	}
+	boosts := map[string]*models.BoostModel{
+		"boost-small": {Id: "boost-1", UserId: "boost-small", Multiplier: 1.2},
+		"boost-large": {Id: "boost-2", UserId: "boost-large", Multiplier: 2},
+	}
+
+	ids := func(users []*models.UserModel) []string {
+		userIds := []string{}
+		for _, user := range users {
+			userIds = append(userIds, user.Id)
+		}
+		return userIds
+	}
+
+	// without the boosts the low scored candidates follow the high one
+	unboosted := ids(service.rankUsers(viewer, newUsers(), nil))
+	if slices.Index(unboosted, "high") > slices.Index(unboosted, "boost-large") {
+		t.Fatalf("unboosted order = %v, want high before the low scored candidates", unboosted)
+	}
+
+	got := ids(service.rankUsers(viewer, newUsers(), boosts))
+	want := []string{"super", "boost-large", "boost-small", "high"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("boosted order = %v, want %v", got, want)
+	}
+}
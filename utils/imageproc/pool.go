@@ -0,0 +1,60 @@
+package imageproc
+
+import (
+	"log"
+	"sync"
+)
+
+// Pool runs jobs on a fixed number of workers with a bounded queue, so a burst
+// of uploads only waits in the queue instead of competing with the http
+// handlers for cpu.
+type Pool struct {
+	jobs chan func()
+	wg   sync.WaitGroup
+}
+
+func NewPool(workers, queueSize int) *Pool {
+	pool := &Pool{
+		jobs: make(chan func(), queueSize),
+	}
+
+	for i := 0; i < workers; i++ {
+		pool.wg.Add(1)
+		go pool.work()
+	}
+
+	return pool
+}
+
+func (pool *Pool) work() {
+	defer pool.wg.Done()
+	for job := range pool.jobs {
+		pool.run(job)
+	}
+}
+
+func (pool *Pool) run(job func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Println("[imageproc][Pool] job panic :", r)
+		}
+	}()
+	job()
+}
+
+// Submit queues the job, it returns false without blocking when the queue is
+// full.
+func (pool *Pool) Submit(job func()) bool {
+	select {
+	case pool.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops accepting jobs and waits for the queued ones to finish.
+func (pool *Pool) Close() {
+	close(pool.jobs)
+	pool.wg.Wait()
+}
@@ -0,0 +1,157 @@
+package imageproc
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	_ "image/png"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	VariantThumbnail = "thumbnail"
+	VariantMedium    = "medium"
+	VariantFull      = "full"
+
+	// maxPixels guards the decoder against decompression bombs, the header
+	// is checked before any pixel is allocated.
+	maxPixels = 50_000_000
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image format")
+	ErrImageTooSmall    = errors.New("image resolution is too small")
+	ErrImageTooLarge    = errors.New("image resolution is too large")
+)
+
+// Variant is one size generated from the original, Size is the maximum
+// length of the longest edge.
+type Variant struct {
+	Name string
+	Size int
+}
+
+var Variants = []Variant{
+	{Name: VariantThumbnail, Size: 240},
+	{Name: VariantMedium, Size: 720},
+	{Name: VariantFull, Size: 1600},
+}
+
+type ConfProcessor struct {
+	MinSize int
+	Quality int
+}
+
+type Processor struct {
+	minSize int
+	quality int
+}
+
+type Result struct {
+	Width    int
+	Height   int
+	BlurHash string
+	// Variants holds the jpeg encoded image per variant name
+	Variants map[string][]byte
+	// Preview is the decoded medium variant, e.g. for a classifier
+	Preview *image.RGBA
+}
+
+func NewProcessor(conf ConfProcessor) *Processor {
+	if conf.Quality == 0 {
+		conf.Quality = 85
+	}
+
+	return &Processor{
+		minSize: conf.MinSize,
+		quality: conf.Quality,
+	}
+}
+
+// Process decodes a jpeg, png or webp image, applies the exif orientation and
+// re-encodes every variant as jpeg. Re-encoding drops all metadata of the
+// original, including the gps position.
+func (p *Processor) Process(data []byte) (*Result, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	img := flatten(src)
+	if format == "jpeg" {
+		img = orient(img, readOrientation(data))
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width < p.minSize || height < p.minSize {
+		return nil, ErrImageTooSmall
+	}
+
+	result := &Result{
+		Width:    width,
+		Height:   height,
+		Variants: map[string][]byte{},
+	}
+
+	for _, variant := range Variants {
+		resized := resize(img, variant.Size)
+		if variant.Name == VariantMedium {
+			result.Preview = resized
+		}
+
+		buf := new(bytes.Buffer)
+		err := jpeg.Encode(buf, resized, &jpeg.Options{Quality: p.quality})
+		if err != nil {
+			return nil, err
+		}
+		result.Variants[variant.Name] = buf.Bytes()
+	}
+
+	result.BlurHash = BlurHash(resize(img, 32), 4, 3)
+
+	return result, nil
+}
+
+// flatten draws the image on a white background, jpeg has no alpha channel.
+func flatten(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Over)
+	return dst
+}
+
+// resize scales the image down so the longest edge fits size, images that
+// already fit are returned as is.
+func resize(src *image.RGBA, size int) *image.RGBA {
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	if width <= size && height <= size {
+		return src
+	}
+
+	if width >= height {
+		height = max(1, height*size/width)
+		width = size
+	} else {
+		width = max(1, width*size/height)
+		height = size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+	return dst
+}
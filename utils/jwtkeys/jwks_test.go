@@ -0,0 +1,59 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestJWKS(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey := writeRSAKey(t, dir, "rsa-1")
+	edKey := writeEd25519Key(t, dir, "ed-1")
+	set := loadKeySet(t, dir, "rsa-1").WithLegacy(NewHMACKey("secret"))
+
+	jwks := set.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("jwks has %d keys, want 2: %+v", len(jwks.Keys), jwks.Keys)
+	}
+
+	ed, rsaJwk := jwks.Keys[0], jwks.Keys[1]
+	if ed.Kid != "ed-1" || ed.Kty != "OKP" || ed.Crv != "Ed25519" || ed.Alg != "EdDSA" || ed.Use != "sig" {
+		t.Errorf("ed25519 jwk = %+v", ed)
+	}
+	if ed.X != base64.RawURLEncoding.EncodeToString(edKey.Public().(ed25519.PublicKey)) {
+		t.Errorf("ed25519 jwk x = %v, want the public key", ed.X)
+	}
+
+	if rsaJwk.Kid != "rsa-1" || rsaJwk.Kty != "RSA" || rsaJwk.Alg != "RS256" || rsaJwk.Use != "sig" {
+		t.Errorf("rsa jwk = %+v", rsaJwk)
+	}
+	n, _ := base64.RawURLEncoding.DecodeString(rsaJwk.N)
+	e, _ := base64.RawURLEncoding.DecodeString(rsaJwk.E)
+	public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	if !public.Equal(&rsaKey.PublicKey) {
+		t.Errorf("rsa jwk does not match the public key")
+	}
+
+	// neither the private parts nor the legacy secret are published
+	content, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+	for _, field := range []string{`"d"`, `"p"`, `"q"`, `"k"`, base64.RawURLEncoding.EncodeToString([]byte("secret"))} {
+		if strings.Contains(string(content), field) {
+			t.Errorf("jwks contains %v: %s", field, content)
+		}
+	}
+}
+
+func TestJWKSWithoutKid(t *testing.T) {
+	jwks := NewHMACKeySet("secret").JWKS()
+	if len(jwks.Keys) != 0 {
+		t.Fatalf("jwks of a shared secret = %+v, want no keys", jwks.Keys)
+	}
+}
@@ -0,0 +1,184 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key is one signing or verification key, Private is nil for keys that are
+// only kept to verify tokens signed before a rotation.
+type Key struct {
+	Id      string
+	Method  jwt.SigningMethod
+	Private interface{}
+	Public  interface{}
+}
+
+// KeySet signs tokens with one key and verifies them with every key it holds,
+// the key of a token is picked by its kid header.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	// legacy verifies tokens without a kid, minted before the key set existed
+	legacy *Key
+}
+
+// NewHMACKeySet keeps the shared secret signing of HS256, the key has no kid
+// and is never published.
+func NewHMACKeySet(secret string) *KeySet {
+	key := NewHMACKey(secret)
+	return &KeySet{
+		signing: key,
+		keys:    map[string]*Key{},
+		legacy:  key,
+	}
+}
+
+func NewHMACKey(secret string) *Key {
+	return &Key{
+		Method:  jwt.SigningMethodHS256,
+		Private: []byte(secret),
+		Public:  []byte(secret),
+	}
+}
+
+// LoadKeySet reads every "<kid>.pem" file of dir, a private key can sign and
+// verify while a public key only verifies. RSA keys sign with RS256 and
+// Ed25519 keys with EdDSA. The key named signingKid signs new tokens, other
+// keys stay valid for tokens they signed so a rotation logs nobody out.
+func LoadKeySet(dir, signingKid string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	set := &KeySet{keys: map[string]*Key{}}
+	for _, file := range files {
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := parseKey(kid, content)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %v : %w", kid, err)
+		}
+		set.keys[kid] = key
+	}
+
+	signing, ok := set.keys[signingKid]
+	if !ok {
+		return nil, fmt.Errorf("jwt signing key %v not found in %v", signingKid, dir)
+	}
+	if signing.Private == nil {
+		return nil, fmt.Errorf("jwt signing key %v has no private key", signingKid)
+	}
+	set.signing = signing
+
+	return set, nil
+}
+
+// WithLegacy returns a copy of the set that verifies tokens without a kid
+// using key.
+func (s *KeySet) WithLegacy(key *Key) *KeySet {
+	set := *s
+	set.legacy = key
+	return &set
+}
+
+// Sign signs the claims with the signing key and sets its kid header.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+	if s.signing.Id != "" {
+		token.Header["kid"] = s.signing.Id
+	}
+
+	return token.SignedString(s.signing.Private)
+}
+
+// Keyfunc returns the verification key of the token for jwt.Parse, a token
+// signed with another algorithm than the one of its key is rejected.
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	key := s.legacy
+	if kid, ok := token.Header["kid"].(string); ok {
+		key = s.keys[kid]
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("unknown key id: %v", token.Header["kid"])
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.Public, nil
+}
+
+// Keys returns the keys with a kid ordered by it.
+func (s *KeySet) Keys() []*Key {
+	keys := make([]*Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Id < keys[j].Id
+	})
+
+	return keys
+}
+
+func parseKey(kid string, content []byte) (*Key, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+
+	var (
+		private interface{}
+		public  crypto.PublicKey
+		err     error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported pem block %v", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if signer, ok := private.(crypto.Signer); ok {
+		public = signer.Public()
+	}
+
+	key := &Key{Id: kid, Private: private, Public: public}
+	switch public.(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", public)
+	}
+
+	return key, nil
+}
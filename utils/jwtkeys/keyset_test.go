@@ -0,0 +1,325 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeRSAKey writes a new RSA private key as "<kid>.pem" into dir and
+// returns it
+func writeRSAKey(t *testing.T, dir, kid string) *rsa.PrivateKey {
+	t.Helper()
+
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	writePem(t, dir, kid, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private))
+	return private
+}
+
+// writeEd25519Key writes a new Ed25519 private key as "<kid>.pem" into dir
+// and returns it
+func writeEd25519Key(t *testing.T, dir, kid string) ed25519.PrivateKey {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+
+	content, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal ed25519 key: %v", err)
+	}
+
+	writePem(t, dir, kid, "PRIVATE KEY", content)
+	return private
+}
+
+// writePublicKey replaces "<kid>.pem" with only the public part of the key
+func writePublicKey(t *testing.T, dir, kid string, public interface{}) {
+	t.Helper()
+
+	content, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+
+	writePem(t, dir, kid, "PUBLIC KEY", content)
+}
+
+func writePem(t *testing.T, dir, kid, blockType string, content []byte) {
+	t.Helper()
+
+	file := filepath.Join(dir, kid+".pem")
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content}), 0600); err != nil {
+		t.Fatalf("write %v: %v", file, err)
+	}
+}
+
+func loadKeySet(t *testing.T, dir, signingKid string) *KeySet {
+	t.Helper()
+
+	set, err := LoadKeySet(dir, signingKid)
+	if err != nil {
+		t.Fatalf("load key set: %v", err)
+	}
+
+	return set
+}
+
+func testClaims() jwt.Claims {
+	return jwt.RegisteredClaims{
+		Subject:   "user-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func sign(t *testing.T, set *KeySet) string {
+	t.Helper()
+
+	token, err := set.Sign(testClaims())
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	return token
+}
+
+func verify(set *KeySet, token string) error {
+	_, err := jwt.Parse(token, set.Keyfunc)
+	return err
+}
+
+// kidOf returns the kid header of the token
+func kidOf(t *testing.T, token string) interface{} {
+	t.Helper()
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("parse token: %v", err)
+	}
+
+	return parsed.Header["kid"]
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey := writeRSAKey(t, dir, "rsa-1")
+	edKey := writeEd25519Key(t, dir, "ed-1")
+	writePublicKey(t, dir, "rsa-0", &writeRSAKey(t, t.TempDir(), "old").PublicKey)
+
+	tests := []struct {
+		name       string
+		signingKid string
+		method     jwt.SigningMethod
+	}{
+		{name: "rsa signs with RS256", signingKid: "rsa-1", method: jwt.SigningMethodRS256},
+		{name: "ed25519 signs with EdDSA", signingKid: "ed-1", method: jwt.SigningMethodEdDSA},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := loadKeySet(t, dir, tt.signingKid)
+
+			token := sign(t, set)
+			if kid := kidOf(t, token); kid != tt.signingKid {
+				t.Fatalf("kid = %v, want %v", kid, tt.signingKid)
+			}
+
+			parsed, err := jwt.Parse(token, set.Keyfunc)
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			if parsed.Method.Alg() != tt.method.Alg() {
+				t.Fatalf("alg = %v, want %v", parsed.Method.Alg(), tt.method.Alg())
+			}
+		})
+	}
+
+	keys := loadKeySet(t, dir, "rsa-1").Keys()
+	ids := []string{}
+	for _, key := range keys {
+		ids = append(ids, key.Id)
+	}
+	if strings.Join(ids, ",") != "ed-1,rsa-0,rsa-1" {
+		t.Fatalf("key ids = %v, want ed-1,rsa-0,rsa-1", ids)
+	}
+	if !keys[2].Public.(*rsa.PublicKey).Equal(&rsaKey.PublicKey) {
+		t.Fatalf("public key of rsa-1 does not match its private key")
+	}
+	if !keys[0].Public.(ed25519.PublicKey).Equal(edKey.Public()) {
+		t.Fatalf("public key of ed-1 does not match its private key")
+	}
+	if keys[1].Private != nil {
+		t.Fatalf("public only key rsa-0 has a private key")
+	}
+}
+
+func TestLoadKeySetErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, dir string)
+		signingKid string
+		want       string
+	}{
+		{
+			name:       "signing key missing",
+			setup:      func(t *testing.T, dir string) { writeRSAKey(t, dir, "rsa-1") },
+			signingKid: "rsa-2",
+			want:       "not found",
+		},
+		{
+			name: "signing key without private key",
+			setup: func(t *testing.T, dir string) {
+				writePublicKey(t, dir, "rsa-1", &writeRSAKey(t, t.TempDir(), "old").PublicKey)
+			},
+			signingKid: "rsa-1",
+			want:       "no private key",
+		},
+		{
+			name: "not a pem file",
+			setup: func(t *testing.T, dir string) {
+				if err := os.WriteFile(filepath.Join(dir, "rsa-1.pem"), []byte("secret"), 0600); err != nil {
+					t.Fatalf("write key: %v", err)
+				}
+			},
+			signingKid: "rsa-1",
+			want:       "no pem block found",
+		},
+		{
+			name:       "unsupported pem block",
+			setup:      func(t *testing.T, dir string) { writePem(t, dir, "rsa-1", "CERTIFICATE", []byte("cert")) },
+			signingKid: "rsa-1",
+			want:       "unsupported pem block",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.setup(t, dir)
+
+			_, err := LoadKeySet(dir, tt.signingKid)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("load key set error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+	keyA := writeRSAKey(t, dir, "key-a")
+	tokenA := sign(t, loadKeySet(t, dir, "key-a"))
+
+	// rotate to key B, A is kept with only its public key to verify
+	writeEd25519Key(t, dir, "key-b")
+	writePublicKey(t, dir, "key-a", &keyA.PublicKey)
+	rotated := loadKeySet(t, dir, "key-b")
+
+	tokenB := sign(t, rotated)
+	if kid := kidOf(t, tokenB); kid != "key-b" {
+		t.Fatalf("kid after rotation = %v, want key-b", kid)
+	}
+
+	for name, token := range map[string]string{"token of key A": tokenA, "token of key B": tokenB} {
+		if err := verify(rotated, token); err != nil {
+			t.Errorf("verify %v after rotation: %v", name, err)
+		}
+	}
+
+	// once A is dropped its tokens are rejected
+	if err := os.Remove(filepath.Join(dir, "key-a.pem")); err != nil {
+		t.Fatalf("remove key A: %v", err)
+	}
+	if err := verify(loadKeySet(t, dir, "key-b"), tokenA); err == nil {
+		t.Fatalf("token of the dropped key A verified")
+	}
+}
+
+func TestKeySetKeyfuncRejects(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "rsa-1")
+	edKey := writeEd25519Key(t, dir, "ed-1")
+	set := loadKeySet(t, dir, "rsa-1").WithLegacy(NewHMACKey("secret"))
+
+	// signs the claims with method and key, setting kid when it is not empty
+	forge := func(method jwt.SigningMethod, key interface{}, kid string) string {
+		token := jwt.NewWithClaims(method, testClaims())
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("sign forged token: %v", err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "unknown kid", token: forge(jwt.SigningMethodEdDSA, edKey, "ed-2")},
+		{name: "ed25519 token with the kid of the rsa key", token: forge(jwt.SigningMethodEdDSA, edKey, "rsa-1")},
+		{name: "hmac token with the kid of the rsa key", token: forge(jwt.SigningMethodHS256, []byte("secret"), "rsa-1")},
+		{name: "hmac token with an unknown kid", token: forge(jwt.SigningMethodHS256, []byte("secret"), "legacy")},
+		{name: "legacy token signed with another algorithm", token: forge(jwt.SigningMethodHS512, []byte("secret"), "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verify(set, tt.token); err == nil {
+				t.Fatalf("forged token verified")
+			}
+		})
+	}
+}
+
+func TestKeySetLegacy(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "rsa-1")
+	loaded := loadKeySet(t, dir, "rsa-1")
+
+	hmac := NewHMACKeySet("secret")
+	legacyToken := sign(t, hmac)
+	if kid := kidOf(t, legacyToken); kid != nil {
+		t.Fatalf("kid of the hmac token = %v, want none", kid)
+	}
+
+	tests := []struct {
+		name    string
+		set     *KeySet
+		token   string
+		wantErr bool
+	}{
+		{name: "hmac set verifies its token", set: hmac, token: legacyToken},
+		{name: "hmac set rejects another secret", set: NewHMACKeySet("other"), token: legacyToken, wantErr: true},
+		{name: "loaded set with legacy verifies the hmac token", set: loaded.WithLegacy(NewHMACKey("secret")), token: legacyToken},
+		{name: "loaded set without legacy rejects the hmac token", set: loaded, token: legacyToken, wantErr: true},
+		{name: "loaded set with legacy still verifies its own token", set: loaded.WithLegacy(NewHMACKey("secret")), token: sign(t, loaded)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verify(tt.set, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verify error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
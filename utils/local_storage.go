@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStorage struct {
+	path    string
+	baseUrl string
+}
+
+// NewLocalStorage writes files under the local path, they are served by the
+// app itself under the base url.
+func NewLocalStorage(conf ConfStorage) (Storage, error) {
+	if err := os.MkdirAll(conf.LocalPath, 0755); err != nil {
+		return nil, err
+	}
+
+	return &localStorage{
+		path:    conf.LocalPath,
+		baseUrl: strings.TrimRight(conf.BaseUrl, "/"),
+	}, nil
+}
+
+func (s *localStorage) Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error) {
+	filePath := filepath.Join(s.path, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return "", err
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, body); err != nil {
+		return "", err
+	}
+
+	return s.baseUrl + "/" + key, nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	err := os.Remove(filepath.Join(s.path, filepath.FromSlash(key)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"image"
+)
+
+const (
+	ClassifierNoop  = "noop"
+	ClassifierRules = "rules"
+)
+
+const (
+	DecisionApprove = "approve"
+	DecisionReject  = "reject"
+	// DecisionReview leaves the photo in the moderation queue for an admin
+	DecisionReview = "review"
+)
+
+type Verdict struct {
+	Decision string
+	Reason   string
+}
+
+// Classifier decides on a photo before it is shown to other users, a real
+// model can be plugged in by implementing it and adding it to NewClassifier.
+type Classifier interface {
+	Classify(ctx context.Context, img image.Image) (Verdict, error)
+}
+
+func NewClassifier(name string) (Classifier, error) {
+	switch name {
+	case ClassifierNoop:
+		return NewNoopClassifier(), nil
+	case ClassifierRules:
+		return NewRulesClassifier(), nil
+	default:
+		return nil, errors.New("unknown classifier " + name)
+	}
+}
@@ -0,0 +1,17 @@
+package moderation
+
+import (
+	"context"
+	"image"
+)
+
+type noopClassifier struct{}
+
+// NewNoopClassifier sends every photo to the admin queue.
+func NewNoopClassifier() Classifier {
+	return &noopClassifier{}
+}
+
+func (c *noopClassifier) Classify(ctx context.Context, img image.Image) (Verdict, error) {
+	return Verdict{Decision: DecisionReview}, nil
+}
@@ -0,0 +1,59 @@
+package moderation
+
+import (
+	"context"
+	"image"
+	"math"
+)
+
+const (
+	// maxAspectRatio rejects banners and screenshots of long pages
+	maxAspectRatio = 3.0
+	// minLuminanceDeviation rejects (almost) single color images
+	minLuminanceDeviation = 4.0
+)
+
+type rulesClassifier struct{}
+
+// NewRulesClassifier rejects photos that are obviously unusable with a few
+// local rules, everything else goes to the admin queue.
+func NewRulesClassifier() Classifier {
+	return &rulesClassifier{}
+}
+
+func (c *rulesClassifier) Classify(ctx context.Context, img image.Image) (Verdict, error) {
+	bounds := img.Bounds()
+	width, height := float64(bounds.Dx()), float64(bounds.Dy())
+
+	if math.Max(width, height)/math.Min(width, height) > maxAspectRatio {
+		return Verdict{Decision: DecisionReject, Reason: "photo aspect ratio is not allowed"}, nil
+	}
+
+	if luminanceDeviation(img) < minLuminanceDeviation {
+		return Verdict{Decision: DecisionReject, Reason: "photo looks blank"}, nil
+	}
+
+	return Verdict{Decision: DecisionReview}, nil
+}
+
+func luminanceDeviation(img image.Image) float64 {
+	bounds := img.Bounds()
+
+	var sum, sumSquare, count float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			luminance := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257
+			sum += luminance
+			sumSquare += luminance * luminance
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	mean := sum / count
+	return math.Sqrt(math.Max(0, sumSquare/count-mean*mean))
+}
@@ -0,0 +1,144 @@
+package ranking
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// recencyHalfLife is how long after the last activity the recency signal
+	// is halved
+	recencyHalfLife = 72 * time.Hour
+	// distanceHalfKm is the distance where the distance signal is halved
+	distanceHalfKm = 10.0
+)
+
+type defaultRanker struct {
+	weights Weights
+	debug   bool
+}
+
+// NewDefaultRanker scores every candidate as the weighted sum of its signals,
+// each signal is normalized between 0 and 1. With debug on, the breakdown of
+// every candidate is logged for tuning the weights.
+func NewDefaultRanker(weights Weights, debug bool) Ranker {
+	return &defaultRanker{
+		weights: weights,
+		debug:   debug,
+	}
+}
+
+func (r *defaultRanker) Rank(viewer Viewer, candidates []Candidate, now time.Time, seed int64) []Score {
+	scores := make([]Score, 0, len(candidates))
+	for _, candidate := range candidates {
+		breakdown := map[string]float64{
+			SignalRecency:         r.weights.Recency * recencyScore(candidate.LastActiveAt, now),
+			SignalDistance:        r.weights.Distance * distanceScore(candidate.DistanceKm),
+			SignalCompleteness:    r.weights.Completeness * clamp(candidate.Completeness),
+			SignalSharedInterests: r.weights.SharedInterests * sharedInterestScore(viewer.Interests, candidate.Interests),
+			SignalDesirability:    r.weights.Desirability * clamp(candidate.Desirability),
+			SignalJitter:          r.weights.Jitter * jitter(seed, candidate.UserId),
+		}
+
+		total := 0.0
+		for _, signal := range Signals {
+			total += breakdown[signal]
+		}
+		if candidate.Boost > 0 {
+			total *= candidate.Boost
+		}
+
+		scores = append(scores, Score{
+			UserId:    candidate.UserId,
+			Total:     total,
+			Breakdown: breakdown,
+		})
+	}
+
+	// the user id breaks ties so the order never depends on the input order
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Total != scores[j].Total {
+			return scores[i].Total > scores[j].Total
+		}
+		return scores[i].UserId < scores[j].UserId
+	})
+
+	if r.debug {
+		for rank, score := range scores {
+			log.Printf("[ranking][Rank] viewer %s seed %d rank %d candidate %s total %.4f recency %.4f distance %.4f completeness %.4f shared_interests %.4f desirability %.4f jitter %.4f",
+				viewer.UserId, seed, rank+1, score.UserId, score.Total,
+				score.Breakdown[SignalRecency], score.Breakdown[SignalDistance], score.Breakdown[SignalCompleteness],
+				score.Breakdown[SignalSharedInterests], score.Breakdown[SignalDesirability], score.Breakdown[SignalJitter])
+		}
+	}
+
+	return scores
+}
+
+func recencyScore(lastActiveAt time.Time, now time.Time) float64 {
+	if lastActiveAt.IsZero() {
+		return 0
+	}
+
+	idle := now.Sub(lastActiveAt)
+	if idle < 0 {
+		idle = 0
+	}
+	return math.Pow(0.5, float64(idle)/float64(recencyHalfLife))
+}
+
+func distanceScore(distanceKm *float64) float64 {
+	if distanceKm == nil {
+		return 0
+	}
+	return distanceHalfKm / (distanceHalfKm + math.Max(0, *distanceKm))
+}
+
+// sharedInterestScore is the jaccard index of both interest sets.
+func sharedInterestScore(a []string, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	set := map[string]bool{}
+	for _, interest := range a {
+		set[strings.ToLower(interest)] = true
+	}
+
+	shared := 0
+	union := len(set)
+	seen := map[string]bool{}
+	for _, interest := range b {
+		interest = strings.ToLower(interest)
+		if seen[interest] {
+			continue
+		}
+		seen[interest] = true
+
+		if set[interest] {
+			shared++
+		} else {
+			union++
+		}
+	}
+
+	return float64(shared) / float64(union)
+}
+
+// jitter is a value between 0 and 1 that only depends on the seed and the
+// user id.
+func jitter(seed int64, userId string) float64 {
+	h := fnv.New64a()
+	_ = binary.Write(h, binary.LittleEndian, seed)
+	h.Write([]byte(userId))
+	return float64(h.Sum64()>>11) / float64(1<<53)
+}
+
+func clamp(value float64) float64 {
+	return math.Max(0, math.Min(1, value))
+}
@@ -0,0 +1,104 @@
+package ranking
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+const goldenSeed int64 = 42
+
+var (
+	goldenNow     = time.Date(2024, 8, 26, 12, 0, 0, 0, time.UTC)
+	goldenWeights = Weights{
+		Recency:         0.25,
+		Distance:        0.2,
+		Completeness:    0.15,
+		SharedInterests: 0.15,
+		Desirability:    0.2,
+		Jitter:          0.05,
+	}
+	goldenViewer = Viewer{
+		UserId:    "viewer",
+		Interests: []string{"hiking", "coffee", "jazz"},
+	}
+)
+
+func goldenCandidates() []Candidate {
+	near, far := 2.0, 35.0
+	return []Candidate{
+		{
+			UserId:       "user-a",
+			LastActiveAt: goldenNow.Add(-time.Hour),
+			DistanceKm:   &near,
+			Completeness: 0.8,
+			Interests:    []string{"Coffee", "jazz"},
+			Desirability: 0.6,
+		},
+		{
+			UserId:       "user-b",
+			LastActiveAt: goldenNow.Add(-96 * time.Hour),
+			DistanceKm:   &far,
+			Completeness: 1,
+			Interests:    []string{"hiking"},
+			Desirability: 0.9,
+		},
+		{
+			UserId:       "user-c",
+			LastActiveAt: goldenNow.Add(-24 * time.Hour),
+			Completeness: 0.5,
+			Desirability: 0.5,
+			Boost:        2,
+		},
+		{
+			UserId:       "user-d",
+			Completeness: 0.2,
+			Interests:    []string{"chess"},
+			Desirability: 0.1,
+		},
+	}
+}
+
+// TestDefaultRankerGolden pins the ranking of a fixed input and seed, a change
+// of the scoring shows up here as a changed total.
+func TestDefaultRankerGolden(t *testing.T) {
+	want := []Score{
+		{UserId: "user-a", Total: 0.7596577549091909},
+		{UserId: "user-c", Total: 0.7576228538227563},
+		{UserId: "user-b", Total: 0.5290433085880424},
+		{UserId: "user-d", Total: 0.055386316276978896},
+	}
+
+	got := NewDefaultRanker(goldenWeights, false).Rank(goldenViewer, goldenCandidates(), goldenNow, goldenSeed)
+	if len(got) != len(want) {
+		t.Fatalf("got %d scores, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		if got[i].UserId != want[i].UserId || got[i].Total != want[i].Total {
+			t.Errorf("rank %d: got %s %v, want %s %v", i+1, got[i].UserId, got[i].Total, want[i].UserId, want[i].Total)
+		}
+	}
+}
+
+// TestDefaultRankerDeterministic ranks the same candidates in different input
+// orders, the totals must match to the last bit.
+func TestDefaultRankerDeterministic(t *testing.T) {
+	ranker := NewDefaultRanker(goldenWeights, false)
+	want := ranker.Rank(goldenViewer, goldenCandidates(), goldenNow, goldenSeed)
+
+	shuffle := rand.New(rand.NewSource(goldenSeed))
+	for run := 0; run < 100; run++ {
+		candidates := goldenCandidates()
+		shuffle.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+
+		got := ranker.Rank(goldenViewer, candidates, goldenNow, goldenSeed)
+		for i := range want {
+			if got[i].UserId != want[i].UserId || got[i].Total != want[i].Total {
+				t.Fatalf("run %d rank %d: got %s %v, want %s %v", run, i+1, got[i].UserId, got[i].Total, want[i].UserId, want[i].Total)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package ranking
+
+import (
+	"time"
+)
+
+const (
+	SignalRecency         = "recency"
+	SignalDistance        = "distance"
+	SignalCompleteness    = "completeness"
+	SignalSharedInterests = "shared_interests"
+	SignalDesirability    = "desirability"
+	SignalJitter          = "jitter"
+)
+
+// Signals lists every signal in a fixed order, the total is summed in this
+// order so the float result never depends on map iteration.
+var Signals = []string{
+	SignalRecency,
+	SignalDistance,
+	SignalCompleteness,
+	SignalSharedInterests,
+	SignalDesirability,
+	SignalJitter,
+}
+
+// Candidate holds the signals of one user in the discovery feed.
+type Candidate struct {
+	UserId       string
+	LastActiveAt time.Time
+	// DistanceKm is nil when either side has no location
+	DistanceKm *float64
+	// Completeness of the profile between 0 and 1
+	Completeness float64
+	Interests    []string
+	// Desirability between 0 and 1
+	Desirability float64
+	// Boost multiplies the total score while the candidate is boosted, 0
+	// means no boost
+	Boost float64
+}
+
+// Viewer is the user the feed is ranked for.
+type Viewer struct {
+	UserId    string
+	Interests []string
+}
+
+type Score struct {
+	UserId string
+	// Total is the sum of the breakdown, multiplied by the boost
+	Total     float64
+	Breakdown map[string]float64
+}
+
+// Ranker orders the candidates of the discovery feed, the result is sorted
+// from best to worst. Now and Seed are passed in so a ranking can always be
+// reproduced.
+type Ranker interface {
+	Rank(viewer Viewer, candidates []Candidate, now time.Time, seed int64) []Score
+}
+
+// Weights of every signal of the default ranker, the jitter weight mixes in a
+// seeded random value so equal candidates are not always in the same order.
+type Weights struct {
+	Recency         float64
+	Distance        float64
+	Completeness    float64
+	SharedInterests float64
+	Desirability    float64
+	Jitter          float64
+}
@@ -0,0 +1,95 @@
+package rating
+
+import "math"
+
+const (
+	// Initial is the rating of a user nobody swiped on yet
+	Initial = 1000.0
+	// DefaultK is how many points a single swipe can move a rating at most
+	DefaultK = 32.0
+	// scale is the rating difference where the higher rated side is expected
+	// to win 10 to 1
+	scale = 400.0
+)
+
+// Outcome is a single swipe, the swiper plays against the target and the
+// target wins with a right swipe.
+type Outcome struct {
+	SwiperId string
+	TargetId string
+	Right    bool
+}
+
+// Elo rates the targets of swipes, a right swipe from a swiper rated higher
+// than the target is unexpected and moves the rating more than one from a
+// swiper rated lower. The rating of the swiper never changes by swiping.
+type Elo struct {
+	K float64
+}
+
+func NewElo(k float64) Elo {
+	return Elo{K: k}
+}
+
+// Update returns the new rating of the target after the outcome.
+func (elo Elo) Update(target, swiper float64, right bool) float64 {
+	result := 0.0
+	if right {
+		result = 1
+	}
+	return target + elo.K*(result-Expected(target, swiper))
+}
+
+// Expected is the chance a swiper with the given rating swipes the target
+// right.
+func Expected(target, swiper float64) float64 {
+	return 1 / (1 + math.Pow(10, (swiper-target)/scale))
+}
+
+// Normalize maps a rating between 0 and 1, a user with the initial rating is
+// in the middle.
+func Normalize(rating float64) float64 {
+	return Expected(rating, Initial)
+}
+
+// Table replays outcomes in memory, it is used to recompute every rating from
+// the stored swipes.
+type Table struct {
+	elo     Elo
+	ratings map[string]float64
+	swipes  map[string]int
+}
+
+func NewTable(elo Elo) *Table {
+	return &Table{
+		elo:     elo,
+		ratings: map[string]float64{},
+		swipes:  map[string]int{},
+	}
+}
+
+func (table *Table) Apply(outcome Outcome) {
+	table.ratings[outcome.TargetId] = table.elo.Update(table.Rating(outcome.TargetId), table.Rating(outcome.SwiperId), outcome.Right)
+	table.swipes[outcome.TargetId]++
+}
+
+func (table *Table) Rating(userId string) float64 {
+	if rating, ok := table.ratings[userId]; ok {
+		return rating
+	}
+	return Initial
+}
+
+// Swipes is how many outcomes the user was the target of.
+func (table *Table) Swipes(userId string) int {
+	return table.swipes[userId]
+}
+
+// UserIds returns every rated user.
+func (table *Table) UserIds() []string {
+	userIds := make([]string, 0, len(table.ratings))
+	for userId := range table.ratings {
+		userIds = append(userIds, userId)
+	}
+	return userIds
+}
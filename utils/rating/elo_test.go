@@ -0,0 +1,42 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEloUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		target float64
+		swiper float64
+		right  bool
+		want   float64
+	}{
+		{name: "right between equals", target: 1000, swiper: 1000, right: true, want: 1016},
+		{name: "left between equals", target: 1000, swiper: 1000, right: false, want: 984},
+		{name: "right from a higher rated swiper", target: 1000, swiper: 1400, right: true, want: 1000 + 32*10.0/11},
+		{name: "right from a lower rated swiper", target: 1000, swiper: 600, right: true, want: 1000 + 32*1.0/11},
+		{name: "left from a higher rated swiper", target: 1000, swiper: 1400, right: false, want: 1000 - 32*1.0/11},
+		{name: "left from a lower rated swiper", target: 1000, swiper: 600, right: false, want: 1000 - 32*10.0/11},
+	}
+
+	elo := NewElo(DefaultK)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := elo.Update(tt.target, tt.swiper, tt.right)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("Update(%v, %v, %v) = %v, want %v", tt.target, tt.swiper, tt.right, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize(Initial); got != 0.5 {
+		t.Fatalf("Normalize(Initial) = %v, want 0.5", got)
+	}
+	if low, high := Normalize(800), Normalize(1200); !(low < 0.5 && high > 0.5) {
+		t.Fatalf("Normalize(800) = %v, Normalize(1200) = %v, want them on both sides of 0.5", low, high)
+	}
+}
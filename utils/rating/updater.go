@@ -0,0 +1,98 @@
+package rating
+
+import (
+	"log"
+	"sync"
+)
+
+// Store reads and writes the ratings of the updater, a user without a stored
+// rating is left out of GetRatings.
+type Store interface {
+	GetRatings(userIds []string) (map[string]float64, error)
+	SaveRating(userId string, rating float64) error
+}
+
+// Updater applies swipe outcomes in the background. A single worker applies
+// them one after another, so two swipes on the same user never overwrite each
+// other's update.
+type Updater struct {
+	store Store
+	elo   Elo
+	jobs  chan Outcome
+	wg    sync.WaitGroup
+	// mu guards closed, a submit after Close is dropped instead of sending on
+	// the closed queue
+	mu     sync.Mutex
+	closed bool
+}
+
+func NewUpdater(store Store, elo Elo, queueSize int) *Updater {
+	updater := &Updater{
+		store: store,
+		elo:   elo,
+		jobs:  make(chan Outcome, queueSize),
+	}
+
+	updater.wg.Add(1)
+	go updater.work()
+
+	return updater
+}
+
+func (updater *Updater) work() {
+	defer updater.wg.Done()
+	for outcome := range updater.jobs {
+		if err := updater.apply(outcome); err != nil {
+			log.Println("[rating][Updater] error apply outcome :", err)
+		}
+	}
+}
+
+func (updater *Updater) apply(outcome Outcome) error {
+	ratings, err := updater.store.GetRatings([]string{outcome.SwiperId, outcome.TargetId})
+	if err != nil {
+		return err
+	}
+
+	target, ok := ratings[outcome.TargetId]
+	if !ok {
+		target = Initial
+	}
+	swiper, ok := ratings[outcome.SwiperId]
+	if !ok {
+		swiper = Initial
+	}
+
+	return updater.store.SaveRating(outcome.TargetId, updater.elo.Update(target, swiper, outcome.Right))
+}
+
+// Submit queues the outcome, it returns false without blocking when the queue
+// is full or the updater is closed. A dropped outcome is picked up by the next
+// full recompute.
+func (updater *Updater) Submit(outcome Outcome) bool {
+	updater.mu.Lock()
+	defer updater.mu.Unlock()
+
+	if updater.closed {
+		return false
+	}
+
+	select {
+	case updater.jobs <- outcome:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops accepting outcomes and waits for the queued ones to be applied.
+func (updater *Updater) Close() {
+	updater.mu.Lock()
+	if !updater.closed {
+		updater.closed = true
+		close(updater.jobs)
+	}
+	updater.mu.Unlock()
+
+	updater.wg.Wait()
+}
@@ -0,0 +1,122 @@
+package rating
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+// memoryStore keeps the ratings in a map
+type memoryStore struct {
+	mu      sync.Mutex
+	ratings map[string]float64
+}
+
+func (s *memoryStore) GetRatings(userIds []string) (map[string]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ratings := map[string]float64{}
+	for _, userId := range userIds {
+		if rating, ok := s.ratings[userId]; ok {
+			ratings[userId] = rating
+		}
+	}
+	return ratings, nil
+}
+
+func (s *memoryStore) SaveRating(userId string, rating float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ratings[userId] = rating
+	return nil
+}
+
+func TestUpdaterAppliesOutcomesInOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		outcomes []Outcome
+	}{
+		{
+			name: "one swipe",
+			outcomes: []Outcome{
+				{SwiperId: "high", TargetId: "target", Right: true},
+			},
+		},
+		{
+			name: "swipes of different swipers",
+			outcomes: []Outcome{
+				{SwiperId: "high", TargetId: "target", Right: true},
+				{SwiperId: "low", TargetId: "target", Right: false},
+				{SwiperId: "new", TargetId: "target", Right: true},
+				{SwiperId: "high", TargetId: "target", Right: false},
+			},
+		},
+		{
+			name: "swipes on a swiper",
+			outcomes: []Outcome{
+				{SwiperId: "low", TargetId: "high", Right: true},
+				{SwiperId: "high", TargetId: "target", Right: true},
+				{SwiperId: "target", TargetId: "low", Right: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initial := map[string]float64{"high": 1400, "low": 600, "target": 1000}
+			store := &memoryStore{ratings: map[string]float64{}}
+			for userId, rating := range initial {
+				store.ratings[userId] = rating
+			}
+
+			// the same outcomes replayed one by one in memory
+			elo := NewElo(DefaultK)
+			want := map[string]float64{}
+			for userId, rating := range initial {
+				want[userId] = rating
+			}
+			for _, outcome := range tt.outcomes {
+				target, ok := want[outcome.TargetId]
+				if !ok {
+					target = Initial
+				}
+				swiper, ok := want[outcome.SwiperId]
+				if !ok {
+					swiper = Initial
+				}
+				want[outcome.TargetId] = elo.Update(target, swiper, outcome.Right)
+			}
+
+			updater := NewUpdater(store, elo, len(tt.outcomes))
+			for _, outcome := range tt.outcomes {
+				if !updater.Submit(outcome) {
+					t.Fatalf("Submit(%+v) dropped the outcome", outcome)
+				}
+			}
+			updater.Close()
+
+			for userId, rating := range want {
+				if got := store.ratings[userId]; math.Abs(got-rating) > 1e-9 {
+					t.Errorf("rating of %v = %v, want %v", userId, got, rating)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdaterSubmitAfterClose(t *testing.T) {
+	store := &memoryStore{ratings: map[string]float64{}}
+	updater := NewUpdater(store, NewElo(DefaultK), 1)
+	updater.Close()
+
+	if updater.Submit(Outcome{SwiperId: "swiper", TargetId: "target", Right: true}) {
+		t.Fatal("Submit after Close queued the outcome")
+	}
+	updater.Close()
+
+	if len(store.ratings) != 0 {
+		t.Fatalf("ratings = %v, want none", store.ratings)
+	}
+}
@@ -3,7 +3,9 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -74,6 +76,53 @@ func (r *Redis) RetrieveKeysFromRedis(key string) []string {
 	return keys
 }
 
+// SaveDataToRedisIfNotExists menyimpan data dalam format JSON di Redis hanya
+// jika kunci belum ada, mengembalikan true jika data disimpan
+func (r *Redis) SaveDataToRedisIfNotExists(key string, data interface{}, duration time.Duration) (bool, error) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+
+	return r.Client.SetNX(ctx, key, jsonBytes, duration).Result()
+}
+
+// IncrementDataInRedis menambah nilai angka di Redis secara atomik
+func (r *Redis) IncrementDataInRedis(key string) (int64, error) {
+	return r.Client.Incr(ctx, key).Result()
+}
+
+// incrementWithExpiryScript menambah nilai dan memberi masa berlaku saat kunci
+// baru dibuat, agar penghitung tidak pernah tersimpan tanpa TTL
+var incrementWithExpiryScript = redis.NewScript(`
+local value = redis.call("INCR", KEYS[1])
+if value == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return value
+`)
+
+// IncrementDataInRedisWithExpiry menambah nilai angka di Redis secara atomik,
+// kunci yang baru dibuat kedaluwarsa setelah duration
+func (r *Redis) IncrementDataInRedisWithExpiry(key string, duration time.Duration) (int64, error) {
+	return incrementWithExpiryScript.Run(ctx, r.Client, []string{key}, duration.Milliseconds()).Int64()
+}
+
+// decrementExistingScript hanya mengurangi nilai jika kunci masih ada, agar
+// kunci yang sudah kedaluwarsa tidak dibuat ulang dengan nilai negatif
+var decrementExistingScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("DECR", KEYS[1])
+end
+return 0
+`)
+
+// DecrementDataInRedis mengurangi nilai angka di Redis secara atomik jika
+// kuncinya masih ada
+func (r *Redis) DecrementDataInRedis(key string) error {
+	return decrementExistingScript.Run(ctx, r.Client, []string{key}).Err()
+}
+
 // DeleteDataFromRedis menghapus data dari Redis berdasarkan kunci
 func (r *Redis) DeleteDataFromRedis(key string) error {
 	err := r.Client.Del(ctx, key).Err()
@@ -83,3 +132,192 @@ func (r *Redis) DeleteDataFromRedis(key string) error {
 
 	return nil
 }
+
+// AddSetMemberToRedis menambahkan member ke redis set dan memperbarui masa berlakunya
+func (r *Redis) AddSetMemberToRedis(key string, member string, duration time.Duration) error {
+	pipe := r.Client.TxPipeline()
+	pipe.SAdd(ctx, key, member)
+	pipe.Expire(ctx, key, duration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveSetMemberFromRedis menghapus member dari redis set
+func (r *Redis) RemoveSetMemberFromRedis(key string, member string) error {
+	err := r.Client.SRem(ctx, key, member).Err()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RetrieveSetMembersFromRedis mengambil gabungan member dari beberapa redis set
+func (r *Redis) RetrieveSetMembersFromRedis(keys ...string) ([]string, error) {
+	members, err := r.Client.SUnion(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// AddSortedSetMemberToRedis menambahkan member dengan skor ke redis sorted set
+func (r *Redis) AddSortedSetMemberToRedis(key string, member string, score float64) error {
+	return r.Client.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// RetrieveSortedSetMembersFromRedis mengambil member redis sorted set dengan
+// skor mulai dari min
+func (r *Redis) RetrieveSortedSetMembersFromRedis(key string, min float64) ([]string, error) {
+	return r.Client.ZRangeByScore(ctx, key, &redis.ZRangeBy{
+		Min: strconv.FormatFloat(min, 'f', -1, 64),
+		Max: "+inf",
+	}).Result()
+}
+
+// RemoveSortedSetMembersFromRedis menghapus member redis sorted set dengan
+// skor sampai max
+func (r *Redis) RemoveSortedSetMembersFromRedis(key string, max float64) error {
+	return r.Client.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatFloat(max, 'f', -1, 64)).Err()
+}
+
+// UpdateDataInRedis memperbarui data JSON di Redis tanpa mengubah masa berlakunya,
+// key yang sudah dihapus atau kedaluwarsa tidak dibuat ulang dan hasilnya false
+func (r *Redis) UpdateDataInRedis(key string, data interface{}) (bool, error) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.Client.SetArgs(ctx, key, jsonBytes, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err()
+	switch {
+	case errors.Is(err, redis.Nil):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SaveHashToRedis menyimpan field ke redis hash dan memperbarui masa berlakunya
+func (r *Redis) SaveHashToRedis(key string, values map[string]interface{}, duration time.Duration) error {
+	pipe := r.Client.TxPipeline()
+	pipe.HSet(ctx, key, values)
+	pipe.Expire(ctx, key, duration)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// saveIfExistsScript menyimpan data hanya jika kunci penjaga KEYS[1] masih ada,
+// dicek dan disimpan secara atomik
+var saveIfExistsScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("SET", KEYS[2], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+// SaveDataToRedisIfExists menyimpan data dalam format JSON di Redis hanya jika
+// guardKey masih ada, mengembalikan false jika guardKey sudah dihapus atau
+// kedaluwarsa. guardKey boleh sama dengan key
+func (r *Redis) SaveDataToRedisIfExists(guardKey, key string, data interface{}, duration time.Duration) (bool, error) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+
+	return saveIfExistsScript.Run(ctx, r.Client, []string{guardKey, key}, jsonBytes, duration.Milliseconds()).Bool()
+}
+
+// addSetMemberIfExistsScript menambahkan member ke set hanya jika kunci penjaga
+// KEYS[1] masih ada
+var addSetMemberIfExistsScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("SADD", KEYS[2], ARGV[1])
+redis.call("PEXPIRE", KEYS[2], ARGV[2])
+return 1
+`)
+
+// AddSetMemberToRedisIfExists menambahkan member ke redis set dan memperbarui
+// masa berlakunya hanya jika guardKey masih ada
+func (r *Redis) AddSetMemberToRedisIfExists(guardKey, key string, member string, duration time.Duration) (bool, error) {
+	return addSetMemberIfExistsScript.Run(ctx, r.Client, []string{guardKey, key}, member, duration.Milliseconds()).Bool()
+}
+
+// saveHashIfExistsScript menyimpan field hash hanya jika kunci penjaga KEYS[1]
+// masih ada
+var saveHashIfExistsScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return 0
+end
+redis.call("HSET", KEYS[2], unpack(ARGV, 2))
+redis.call("PEXPIRE", KEYS[2], ARGV[1])
+return 1
+`)
+
+// SaveHashToRedisIfExists menyimpan field ke redis hash dan memperbarui masa
+// berlakunya hanya jika guardKey masih ada
+func (r *Redis) SaveHashToRedisIfExists(guardKey, key string, values map[string]interface{}, duration time.Duration) (bool, error) {
+	args := []interface{}{duration.Milliseconds()}
+	for name, value := range values {
+		args = append(args, name, value)
+	}
+
+	return saveHashIfExistsScript.Run(ctx, r.Client, []string{guardKey, key}, args...).Bool()
+}
+
+// RetrieveHashFromRedis mengambil semua field redis hash, hasilnya kosong
+// jika kunci tidak ada
+func (r *Redis) RetrieveHashFromRedis(key string) (map[string]string, error) {
+	return r.Client.HGetAll(ctx, key).Result()
+}
+
+// compareAndSwapHashScript mengganti field hanya jika nilainya masih sama dengan
+// nilai yang diharapkan, isi hash sebelum perubahan selalu dikembalikan
+var compareAndSwapHashScript = redis.NewScript(`
+local data = redis.call("HGETALL", KEYS[1])
+if redis.call("HGET", KEYS[1], ARGV[1]) ~= ARGV[2] then
+	return {0, data}
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[3], unpack(ARGV, 4))
+return {1, data}
+`)
+
+// CompareAndSwapHashFieldInRedis mengganti nilai field redis hash secara atomik
+// jika nilainya sama dengan old, field pada values ikut disimpan saat berhasil.
+// Mengembalikan apakah field diganti beserta isi hash sebelum perubahan
+func (r *Redis) CompareAndSwapHashFieldInRedis(key, field, old, new string, values map[string]interface{}) (bool, map[string]string, error) {
+	args := []interface{}{field, old, new}
+	for name, value := range values {
+		args = append(args, name, value)
+	}
+
+	res, err := compareAndSwapHashScript.Run(ctx, r.Client, []string{key}, args...).Slice()
+	if err != nil {
+		return false, nil, err
+	}
+
+	swapped, _ := res[0].(int64)
+	pairs, _ := res[1].([]interface{})
+	data := make(map[string]string, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		name, _ := pairs[i].(string)
+		value, _ := pairs[i+1].(string)
+		data[name] = value
+	}
+
+	return swapped == 1, data, nil
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type s3Storage struct {
+	client  *minio.Client
+	bucket  string
+	baseUrl string
+}
+
+// NewS3Storage works with any s3 compatible service, locally it can be pointed
+// to a MinIO container. The bucket is created when it does not exist yet.
+func NewS3Storage(conf ConfStorage) (Storage, error) {
+	client, err := minio.New(conf.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(conf.S3AccessKey, conf.S3SecretKey, ""),
+		Secure: conf.S3UseSSL,
+		Region: conf.S3Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := client.BucketExists(ctx, conf.S3Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		err = client.MakeBucket(ctx, conf.S3Bucket, minio.MakeBucketOptions{Region: conf.S3Region})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	baseUrl := strings.TrimRight(conf.BaseUrl, "/")
+	if baseUrl == "" {
+		scheme := "http"
+		if conf.S3UseSSL {
+			scheme = "https"
+		}
+		baseUrl = fmt.Sprintf("%v://%v/%v", scheme, conf.S3Endpoint, conf.S3Bucket)
+	}
+
+	return &s3Storage{
+		client:  client,
+		bucket:  conf.S3Bucket,
+		baseUrl: baseUrl,
+	}, nil
+}
+
+func (s *s3Storage) Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error) {
+	_, err := s.client.PutObject(ctx, s.bucket, key, body, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return s.baseUrl + "/" + key, nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
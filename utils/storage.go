@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+type ConfStorage struct {
+	Driver      string
+	LocalPath   string
+	BaseUrl     string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+}
+
+// Storage keeps user uploaded files, Upload returns the public url of the file.
+type Storage interface {
+	Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func NewStorage(conf ConfStorage) (Storage, error) {
+	switch conf.Driver {
+	case StorageLocal:
+		return NewLocalStorage(conf)
+	case StorageS3:
+		return NewS3Storage(conf)
+	default:
+		return nil, errors.New("unknown storage driver " + conf.Driver)
+	}
+}
@@ -0,0 +1,200 @@
+package tokenstore
+
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/utils"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type redisStore struct {
+	redis *utils.Redis
+}
+
+func NewRedisStore(redis *utils.Redis) Store {
+	return &redisStore{redis: redis}
+}
+
+func sessionKey(userId, sid string) string {
+	return fmt.Sprintf("session:%v:%v", userId, sid)
+}
+
+func sessionsKey(userId string) string {
+	return fmt.Sprintf("sessions:%v", userId)
+}
+
+func accessTokenKey(userId, sid string) string {
+	return fmt.Sprintf("metaat:%v:%v", userId, sid)
+}
+
+func refreshTokenKey(userId, sid string) string {
+	return fmt.Sprintf("metart:%v:%v", userId, sid)
+}
+
+func (s *redisStore) SaveSession(session *models.SessionModel, ttl time.Duration) error {
+	if err := s.redis.SaveDataToRedis(sessionKey(session.UserId, session.Id), session, ttl); err != nil {
+		return err
+	}
+
+	return s.redis.AddSetMemberToRedis(sessionsKey(session.UserId), session.Id, ttl)
+}
+
+func (s *redisStore) RenewSession(session *models.SessionModel, ttl time.Duration) error {
+	key := sessionKey(session.UserId, session.Id)
+	saved, err := s.redis.SaveDataToRedisIfExists(key, key, session, ttl)
+	if err != nil {
+		return err
+	}
+
+	// the index is only extended while the session is there, a revoke in
+	// between leaves it out
+	if saved {
+		saved, err = s.redis.AddSetMemberToRedisIfExists(key, sessionsKey(session.UserId), session.Id, ttl)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !saved {
+		return ErrSessionRevoked
+	}
+
+	return nil
+}
+
+func (s *redisStore) UpdateSession(session *models.SessionModel) error {
+	// a session revoked or expired since it was read is not brought back
+	_, err := s.redis.UpdateDataInRedis(sessionKey(session.UserId, session.Id), session)
+	return err
+}
+
+func (s *redisStore) RetrieveSession(userId, sid string) (*models.SessionModel, error) {
+	var session models.SessionModel
+	err := s.redis.RetrieveDataFromRedis(sessionKey(userId, sid), &session)
+	switch {
+	case errors.Is(err, redis.Nil):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (s *redisStore) RetrieveSessionIds(userId string) ([]string, error) {
+	return s.redis.RetrieveSetMembersFromRedis(sessionsKey(userId))
+}
+
+func (s *redisStore) SaveAccessToken(meta models.TokenMetaData, ttl time.Duration) error {
+	saved, err := s.redis.SaveDataToRedisIfExists(sessionKey(meta.Id, meta.Sid), accessTokenKey(meta.Id, meta.Sid), meta, ttl)
+	if err != nil {
+		return err
+	}
+
+	if !saved {
+		return ErrSessionRevoked
+	}
+
+	return nil
+}
+
+func (s *redisStore) RetrieveAccessToken(userId, sid string) (*models.TokenMetaData, error) {
+	var meta models.TokenMetaData
+	err := s.redis.RetrieveDataFromRedis(accessTokenKey(userId, sid), &meta)
+	switch {
+	case errors.Is(err, redis.Nil):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return &meta, nil
+}
+
+func (s *redisStore) SaveRefreshToken(userId, sid, rtId string, ttl time.Duration) error {
+	// the rotation history of the family is left as it is
+	saved, err := s.redis.SaveHashToRedisIfExists(sessionKey(userId, sid), refreshTokenKey(userId, sid), map[string]interface{}{
+		"current": rtId,
+	}, ttl)
+	if err != nil {
+		return err
+	}
+
+	if !saved {
+		return ErrSessionRevoked
+	}
+
+	return nil
+}
+
+func (s *redisStore) RetrieveRefreshFamily(userId, sid string) (*RefreshFamily, error) {
+	data, err := s.redis.RetrieveHashFromRedis(refreshTokenKey(userId, sid))
+	if err != nil {
+		return nil, err
+	}
+
+	return newRefreshFamily(data), nil
+}
+
+func (s *redisStore) RotateRefreshToken(userId, sid, presented, next string, rotatedAt time.Time) (bool, *RefreshFamily, error) {
+	swapped, data, err := s.redis.CompareAndSwapHashFieldInRedis(refreshTokenKey(userId, sid), "current", presented, next, map[string]interface{}{
+		"previous":   presented,
+		"rotated_at": rotatedAt.UnixMilli(),
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	return swapped, newRefreshFamily(data), nil
+}
+
+func (s *redisStore) Revoke(userId, sid string) error {
+	sids := []string{sid}
+	if sid == AllSessions {
+		ids, err := s.RetrieveSessionIds(userId)
+		if err != nil {
+			return err
+		}
+		sids = ids
+	}
+
+	for _, id := range sids {
+		keys := []string{
+			sessionKey(userId, id),
+			accessTokenKey(userId, id),
+			refreshTokenKey(userId, id),
+		}
+		for _, key := range keys {
+			if err := s.redis.DeleteDataFromRedis(key); err != nil {
+				return err
+			}
+		}
+
+		if err := s.redis.RemoveSetMemberFromRedis(sessionsKey(userId), id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newRefreshFamily returns nil for an empty hash, the family is gone
+func newRefreshFamily(data map[string]string) *RefreshFamily {
+	if len(data) == 0 {
+		return nil
+	}
+
+	family := &RefreshFamily{
+		Current:  data["current"],
+		Previous: data["previous"],
+	}
+	if millis, err := strconv.ParseInt(data["rotated_at"], 10, 64); err == nil {
+		family.RotatedAt = time.UnixMilli(millis)
+	}
+
+	return family
+}
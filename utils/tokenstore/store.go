@@ -0,0 +1,56 @@
+package tokenstore
+
+import (
+	"dating-app-api/entities/models"
+	"errors"
+	"time"
+)
+
+// AllSessions revokes every session of the user at once
+const AllSessions = "*"
+
+// ErrSessionRevoked is returned by the writes that only apply to a session
+// that still exists, the session was revoked or expired in the meantime
+var ErrSessionRevoked = errors.New("session revoked")
+
+// RefreshFamily is the refresh token history of one session, only Current is
+// accepted and Previous is kept to tell a concurrent refresh from a reuse.
+type RefreshFamily struct {
+	Current   string
+	Previous  string
+	RotatedAt time.Time
+}
+
+// Store owns where sessions, access tokens and refresh tokens are kept, the
+// rest of the app never builds their keys itself.
+type Store interface {
+	SaveSession(session *models.SessionModel, ttl time.Duration) error
+	// RenewSession stores the session for ttl again, it returns
+	// ErrSessionRevoked when the session no longer exists
+	RenewSession(session *models.SessionModel, ttl time.Duration) error
+	// UpdateSession keeps the expiry the session already has, a session that
+	// no longer exists is left out
+	UpdateSession(session *models.SessionModel) error
+	// RetrieveSession returns nil when the session does not exist or has expired
+	RetrieveSession(userId, sid string) (*models.SessionModel, error)
+	// RetrieveSessionIds may still list sessions that expired on their own
+	RetrieveSessionIds(userId string) ([]string, error)
+
+	// SaveAccessToken returns ErrSessionRevoked when the session of meta no
+	// longer exists
+	SaveAccessToken(meta models.TokenMetaData, ttl time.Duration) error
+	RetrieveAccessToken(userId, sid string) (*models.TokenMetaData, error)
+
+	// SaveRefreshToken makes rtId the current token of the session family, it
+	// returns ErrSessionRevoked when the session no longer exists
+	SaveRefreshToken(userId, sid, rtId string, ttl time.Duration) error
+	RetrieveRefreshFamily(userId, sid string) (*RefreshFamily, error)
+	// RotateRefreshToken replaces presented by next only if presented is still
+	// the current token, the family before the change is returned either way
+	RotateRefreshToken(userId, sid, presented, next string, rotatedAt time.Time) (bool, *RefreshFamily, error)
+
+	// Revoke removes the session with its access and refresh tokens, sid may be
+	// AllSessions. The session goes first, so a token written concurrently is
+	// either refused or removed after it
+	Revoke(userId, sid string) error
+}
@@ -0,0 +1,233 @@
+// Package tokenstoretest provides an in memory token store for the tests of
+// the packages that sign in, refresh and revoke sessions.
+package tokenstoretest
+
+import (
+	"dating-app-api/entities/models"
+	"dating-app-api/helpers"
+	"dating-app-api/utils/tokenstore"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// memoryEntry is a stored value with the time it expires at
+type memoryEntry struct {
+	value    interface{}
+	expireAt time.Time
+}
+
+// memoryStore keeps everything in process memory and expires it by the
+// clock, it is never shared between instances.
+type memoryStore struct {
+	mu      sync.Mutex
+	clock   helpers.Clock
+	entries map[string]*memoryEntry
+	// sessions indexes the session ids of every user like the sessions set
+	sessions map[string]map[string]bool
+}
+
+func NewMemoryStore(clock helpers.Clock) tokenstore.Store {
+	return &memoryStore{
+		clock:    clock,
+		entries:  map[string]*memoryEntry{},
+		sessions: map[string]map[string]bool{},
+	}
+}
+
+func sessionKey(userId, sid string) string {
+	return fmt.Sprintf("session:%v:%v", userId, sid)
+}
+
+func accessTokenKey(userId, sid string) string {
+	return fmt.Sprintf("metaat:%v:%v", userId, sid)
+}
+
+func refreshTokenKey(userId, sid string) string {
+	return fmt.Sprintf("metart:%v:%v", userId, sid)
+}
+
+// get returns the live entry of key, an expired entry is dropped
+func (s *memoryStore) get(key string) *memoryEntry {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+
+	if !s.clock.Now().Before(entry.expireAt) {
+		delete(s.entries, key)
+		return nil
+	}
+
+	return entry
+}
+
+func (s *memoryStore) set(key string, value interface{}, ttl time.Duration) {
+	s.entries[key] = &memoryEntry{
+		value:    value,
+		expireAt: s.clock.Now().Add(ttl),
+	}
+}
+
+func (s *memoryStore) SaveSession(session *models.SessionModel, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(sessionKey(session.UserId, session.Id), *session, ttl)
+	if s.sessions[session.UserId] == nil {
+		s.sessions[session.UserId] = map[string]bool{}
+	}
+	s.sessions[session.UserId][session.Id] = true
+
+	return nil
+}
+
+func (s *memoryStore) RenewSession(session *models.SessionModel, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.get(sessionKey(session.UserId, session.Id)) == nil {
+		return tokenstore.ErrSessionRevoked
+	}
+
+	s.set(sessionKey(session.UserId, session.Id), *session, ttl)
+	return nil
+}
+
+func (s *memoryStore) UpdateSession(session *models.SessionModel) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry := s.get(sessionKey(session.UserId, session.Id)); entry != nil {
+		entry.value = *session
+	}
+
+	return nil
+}
+
+func (s *memoryStore) RetrieveSession(userId, sid string) (*models.SessionModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.get(sessionKey(userId, sid))
+	if entry == nil {
+		return nil, nil
+	}
+
+	session := entry.value.(models.SessionModel)
+	return &session, nil
+}
+
+func (s *memoryStore) RetrieveSessionIds(userId string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := []string{}
+	for id := range s.sessions[userId] {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (s *memoryStore) SaveAccessToken(meta models.TokenMetaData, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.get(sessionKey(meta.Id, meta.Sid)) == nil {
+		return tokenstore.ErrSessionRevoked
+	}
+
+	s.set(accessTokenKey(meta.Id, meta.Sid), meta, ttl)
+	return nil
+}
+
+func (s *memoryStore) RetrieveAccessToken(userId, sid string) (*models.TokenMetaData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.get(accessTokenKey(userId, sid))
+	if entry == nil {
+		return nil, nil
+	}
+
+	meta := entry.value.(models.TokenMetaData)
+	return &meta, nil
+}
+
+func (s *memoryStore) SaveRefreshToken(userId, sid, rtId string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.get(sessionKey(userId, sid)) == nil {
+		return tokenstore.ErrSessionRevoked
+	}
+
+	// the rotation history of the family is left as it is
+	family := tokenstore.RefreshFamily{}
+	if entry := s.get(refreshTokenKey(userId, sid)); entry != nil {
+		family = entry.value.(tokenstore.RefreshFamily)
+	}
+	family.Current = rtId
+
+	s.set(refreshTokenKey(userId, sid), family, ttl)
+	return nil
+}
+
+func (s *memoryStore) RetrieveRefreshFamily(userId, sid string) (*tokenstore.RefreshFamily, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.get(refreshTokenKey(userId, sid))
+	if entry == nil {
+		return nil, nil
+	}
+
+	family := entry.value.(tokenstore.RefreshFamily)
+	return &family, nil
+}
+
+func (s *memoryStore) RotateRefreshToken(userId, sid, presented, next string, rotatedAt time.Time) (bool, *tokenstore.RefreshFamily, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.get(refreshTokenKey(userId, sid))
+	if entry == nil {
+		return false, nil, nil
+	}
+
+	family := entry.value.(tokenstore.RefreshFamily)
+	if family.Current != presented {
+		return false, &family, nil
+	}
+
+	entry.value = tokenstore.RefreshFamily{
+		Current:   next,
+		Previous:  presented,
+		RotatedAt: time.UnixMilli(rotatedAt.UnixMilli()),
+	}
+
+	return true, &family, nil
+}
+
+func (s *memoryStore) Revoke(userId, sid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sids := []string{sid}
+	if sid == tokenstore.AllSessions {
+		sids = []string{}
+		for id := range s.sessions[userId] {
+			sids = append(sids, id)
+		}
+	}
+
+	for _, id := range sids {
+		delete(s.entries, accessTokenKey(userId, id))
+		delete(s.entries, refreshTokenKey(userId, id))
+		delete(s.entries, sessionKey(userId, id))
+		delete(s.sessions[userId], id)
+	}
+
+	return nil
+}